@@ -342,7 +342,7 @@ func ValidateAlertCreate(req AlertCreateRequest) error {
 //	})
 func (c *Client) ListAlerts(opts AlertListOptions) ([]Alert, error) {
 	params := url.Values{}
-	params.Set("key", c.key)
+	params.Set("key", c.APIKey())
 	if opts.ProjectUUID != "" {
 		params.Set("project_uuid", opts.ProjectUUID)
 	}
@@ -365,7 +365,7 @@ func (c *Client) ListAlerts(opts AlertListOptions) ([]Alert, error) {
 // projectUUID is optional; empty string means "all projects".
 func (c *Client) CountActiveAlerts(projectUUID string) (*AlertCountActiveResult, error) {
 	params := url.Values{}
-	params.Set("key", c.key)
+	params.Set("key", c.APIKey())
 	if projectUUID != "" {
 		params.Set("project_uuid", projectUUID)
 	}
@@ -386,7 +386,7 @@ func (c *Client) GetAlert(alertUUID string) (*Alert, error) {
 		return nil, fmt.Errorf("scrapfly: GetAlert: alertUUID is required")
 	}
 	params := url.Values{}
-	params.Set("key", c.key)
+	params.Set("key", c.APIKey())
 	var out Alert
 	if err := c.alertGetJSON("/alert/"+url.PathEscape(alertUUID), params, &out); err != nil {
 		return nil, err
@@ -400,7 +400,7 @@ func (c *Client) GetAlert(alertUUID string) (*Alert, error) {
 // dimensions and native bucket grain.
 func (c *Client) ListAlertMetricFamilies() ([]AlertMetricFamily, error) {
 	params := url.Values{}
-	params.Set("key", c.key)
+	params.Set("key", c.APIKey())
 	var out []AlertMetricFamily
 	if err := c.alertGetJSON("/alert/metric-families", params, &out); err != nil {
 		return nil, err
@@ -417,7 +417,7 @@ func (c *Client) GetAlertSeries(alertUUID string, rangeMinutes int) (*AlertSerie
 		return nil, fmt.Errorf("scrapfly: GetAlertSeries: alertUUID is required")
 	}
 	params := url.Values{}
-	params.Set("key", c.key)
+	params.Set("key", c.APIKey())
 	if rangeMinutes > 0 {
 		params.Set("range_minutes", strconv.Itoa(rangeMinutes))
 	}
@@ -545,13 +545,13 @@ func (c *Client) TestAlert(alertUUID string) (*AlertTestResult, error) {
 // a JSON body into out. Wraps non-2xx via the shared error mapper so
 // callers get *APIError just like every other SDK method.
 func (c *Client) alertGetJSON(path string, params url.Values, out any) error {
-	u, _ := url.Parse(c.host + path)
+	u, _ := url.Parse(c.endpoint(path))
 	u.RawQuery = params.Encode()
 	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 	req.Header.Set("Accept", "application/json")
 	return c.alertExec(req, out)
 }
@@ -564,9 +564,9 @@ func (c *Client) alertPostJSON(path string, body, out any) error {
 // alertDoJSON issues a request with a JSON body and decodes a JSON
 // response. body may be nil for verb-only calls (e.g. DELETE).
 func (c *Client) alertDoJSON(method, path string, body, out any) error {
-	u, _ := url.Parse(c.host + path)
+	u, _ := url.Parse(c.endpoint(path))
 	params := url.Values{}
-	params.Set("key", c.key)
+	params.Set("key", c.APIKey())
 	u.RawQuery = params.Encode()
 
 	var reader io.Reader
@@ -581,7 +581,7 @@ func (c *Client) alertDoJSON(method, path string, body, out any) error {
 	if err != nil {
 		return err
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 	req.Header.Set("Accept", "application/json")
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
@@ -593,7 +593,7 @@ func (c *Client) alertDoJSON(method, path string, body, out any) error {
 // delegates to handleAPIErrorResponse (shared with monitoring + scrape),
 // keeping error shapes consistent across the whole SDK.
 func (c *Client) alertExec(req *http.Request, out any) error {
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
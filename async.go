@@ -0,0 +1,192 @@
+package scrapfly
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// defaultAsyncPollInterval is used by WaitForScrape and WaitForExtraction
+// when no interval is given.
+const defaultAsyncPollInterval = 2 * time.Second
+
+// WaitForScrape polls the result of an asynchronous scrape (one submitted
+// with ScrapeConfig.Webhook set) until it reaches a terminal state or timeout
+// elapses. A timeout <= 0 waits forever.
+//
+// The uuid is the ScrapeResult.UUID returned by the initial Scrape call.
+// Returns ErrScrapeTimeout when the scrape is still running at the deadline.
+// An unknown uuid, like any 4xx answer, ends polling with the API error
+// (HTTPStatusCode 404) instead of being polled again.
+//
+// Example:
+//
+//	submitted, err := client.Scrape(&scrapfly.ScrapeConfig{
+//	    URL:     "https://example.com",
+//	    Webhook: "my-webhook",
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	result, err := client.WaitForScrape(submitted.UUID, 2*time.Second, time.Minute)
+func (c *Client) WaitForScrape(uuid string, pollInterval, timeout time.Duration) (*ScrapeResult, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	result, err := c.WaitForScrapeWithContext(ctx, uuid, pollInterval)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w: scrape %s did not finish within %s", ErrScrapeTimeout, uuid, timeout)
+	}
+	return result, err
+}
+
+// WaitForScrapeWithContext is like WaitForScrape but stops polling when ctx
+// is done, returning ctx.Err(). The scrape itself keeps running on the API,
+// which can't cancel it.
+func (c *Client) WaitForScrapeWithContext(ctx context.Context, uuid string, pollInterval time.Duration) (*ScrapeResult, error) {
+	if uuid == "" {
+		return nil, fmt.Errorf("%w: uuid is required", ErrScrapeConfig)
+	}
+	if pollInterval <= 0 {
+		pollInterval = defaultAsyncPollInterval
+	}
+	for {
+		result, err := c.fetchScrapeResult(ctx, uuid)
+		if err != nil {
+			return nil, err
+		}
+		if !isAsyncPending(result.Result.Status) {
+			return c.finishScrapeResult(ctx, result, false)
+		}
+		DefaultLogger.Debug("scrape still pending", "uuid", uuid)
+		if err := sleepContext(ctx, pollInterval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// fetchScrapeResult fetches the current state of an asynchronous scrape.
+func (c *Client) fetchScrapeResult(ctx context.Context, uuid string) (*ScrapeResult, error) {
+	var result ScrapeResult
+	if err := c.fetchAsyncResult(ctx, "/scrape/"+url.PathEscape(uuid), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// WaitForExtraction polls the result of an asynchronous extraction (one
+// submitted with ExtractionConfig.Webhook set) until it reaches a terminal
+// state or timeout elapses. A timeout <= 0 waits forever.
+//
+// The uuid is the ExtractionResult.UUID returned by the initial Extract call.
+// Returns ErrExtractionTimeout when the extraction is still running at the deadline.
+// An unknown uuid, like any 4xx answer, ends polling with the API error
+// (HTTPStatusCode 404) instead of being polled again.
+//
+// Example:
+//
+//	submitted, err := client.Extract(&scrapfly.ExtractionConfig{
+//	    Body:             document,
+//	    ContentType:      "application/pdf",
+//	    ExtractionPrompt: "summarize the contract",
+//	    Webhook:          "my-webhook",
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	result, err := client.WaitForExtraction(submitted.UUID, 5*time.Second, 5*time.Minute)
+func (c *Client) WaitForExtraction(uuid string, pollInterval, timeout time.Duration) (*ExtractionResult, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	result, err := c.WaitForExtractionWithContext(ctx, uuid, pollInterval)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w: extraction %s did not finish within %s", ErrExtractionTimeout, uuid, timeout)
+	}
+	return result, err
+}
+
+// WaitForExtractionWithContext is like WaitForExtraction but stops polling
+// when ctx is done, returning ctx.Err().
+func (c *Client) WaitForExtractionWithContext(ctx context.Context, uuid string, pollInterval time.Duration) (*ExtractionResult, error) {
+	if uuid == "" {
+		return nil, fmt.Errorf("%w: uuid is required", ErrExtractionConfig)
+	}
+	if pollInterval <= 0 {
+		pollInterval = defaultAsyncPollInterval
+	}
+	for {
+		var result ExtractionResult
+		if err := c.fetchAsyncResult(ctx, "/extraction/"+url.PathEscape(uuid), &result); err != nil {
+			return nil, err
+		}
+		if !isAsyncPending(result.Status) {
+			if result.Error != nil {
+				return nil, fmt.Errorf("%w: %w", ErrExtractionAPIFailed, &APIError{
+					Message:          result.Error.Message,
+					Code:             result.Error.Code,
+					DocumentationURL: result.Error.DocURL,
+					Retryable:        result.Error.Retryable,
+				})
+			}
+			return &result, nil
+		}
+		DefaultLogger.Debug("extraction still pending", "uuid", uuid)
+		if err := sleepContext(ctx, pollInterval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// fetchAsyncResult GETs the state of an asynchronous job at path and decodes
+// it into out.
+func (c *Client) fetchAsyncResult(ctx context.Context, path string, out interface{}) error {
+	endpointURL, _ := url.Parse(c.endpoint(path))
+	params := url.Values{}
+	params.Set("key", c.APIKey())
+	endpointURL.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpointURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", c.userAgent())
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := fetchWithRetry(c, req, defaultRetries, defaultDelay)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := c.readResponseBody(resp)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return c.handleAPIErrorResponse(resp, bodyBytes)
+	}
+	if err := json.Unmarshal(bodyBytes, out); err != nil {
+		return fmt.Errorf("failed to unmarshal async result: %w", err)
+	}
+	return nil
+}
+
+// isAsyncPending reports whether an asynchronous job status is not final yet.
+func isAsyncPending(status string) bool {
+	switch status {
+	case "PENDING", "QUEUED", "RUNNING":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package scrapfly
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_WaitForScrapePollsUntilDone(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/scrape/abc-123" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			_, _ = w.Write([]byte(`{"result": {"success": false, "status": "QUEUED"}}`))
+		case 2:
+			_, _ = w.Write([]byte(`{"result": {"success": false, "status": "PENDING"}}`))
+		default:
+			_, _ = w.Write([]byte(scrapeDoneResponse))
+		}
+	})
+
+	result, err := client.WaitForScrape("abc-123", time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Result.Content != "ok" {
+		t.Errorf("content = %q", result.Result.Content)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestClient_WaitForScrapeTimeout(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"result": {"success": false, "status": "RUNNING"}}`))
+	})
+
+	_, err := client.WaitForScrape("abc-123", 5*time.Millisecond, 30*time.Millisecond)
+	if !errors.Is(err, ErrScrapeTimeout) {
+		t.Fatalf("expected ErrScrapeTimeout, got %v", err)
+	}
+}
+
+func TestClient_WaitForUnknownUUIDStops(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"code": "ERR::SCRAPE::NOT_FOUND", "message": "not found"}`))
+	})
+
+	var apiErr *APIError
+	if _, err := client.WaitForScrape("unknown", time.Millisecond, 0); !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusNotFound {
+		t.Errorf("WaitForScrape: expected a 404 APIError, got %v", err)
+	}
+	if _, err := client.WaitForExtraction("unknown", time.Millisecond, 0); !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusNotFound {
+		t.Errorf("WaitForExtraction: expected a 404 APIError, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("calls = %d, want one poll each", got)
+	}
+}
+
+func TestClient_ExtractAsyncThenWait(t *testing.T) {
+	var polls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/extraction":
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"uuid": "ext-42", "status": "PENDING"}`))
+		case "/extraction/ext-42":
+			if atomic.AddInt32(&polls, 1) == 1 {
+				_, _ = w.Write([]byte(`{"uuid": "ext-42", "status": "RUNNING"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"uuid": "ext-42", "status": "DONE", "content_type": "application/json", "data": {"title": "Hello"}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+
+	submitted, err := client.Extract(&ExtractionConfig{
+		Body:             []byte("<html><h1>Hello</h1></html>"),
+		ContentType:      "text/html",
+		ExtractionPrompt: "title",
+		Webhook:          "my-webhook",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if submitted.UUID != "ext-42" {
+		t.Fatalf("uuid = %q", submitted.UUID)
+	}
+
+	result, err := client.WaitForExtraction(submitted.UUID, time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, ok := result.Data.(map[string]interface{})
+	if !ok || data["title"] != "Hello" {
+		t.Errorf("data = %v", result.Data)
+	}
+}
+
+func TestClient_WaitForExtractionFailure(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"uuid": "ext-42", "status": "FAILED", "error": {"code": "ERR::EXTRACTION::TIMEOUT", "message": "timed out"}}`))
+	})
+
+	_, err := client.WaitForExtraction("ext-42", time.Millisecond, time.Second)
+	if !errors.Is(err, ErrExtractionAPIFailed) {
+		t.Fatalf("expected ErrExtractionAPIFailed, got %v", err)
+	}
+}
@@ -84,6 +84,7 @@ func (c *Client) ScrapeBatchWithOptions(configs []*ScrapeConfig, opts BatchOptio
 	bodyConfigs := make([]map[string]string, 0, len(configs))
 
 	for i, cfg := range configs {
+		cfg = cfg.withDefaults(c.defaultScrapeConfig)
 		if cfg.CorrelationID == "" {
 			return nil, fmt.Errorf("ScrapeBatch: configs[%d] is missing CorrelationID (required for matching streamed parts)", i)
 		}
@@ -126,8 +127,8 @@ func (c *Client) ScrapeBatchWithOptions(configs []*ScrapeConfig, opts BatchOptio
 		return nil, fmt.Errorf("ScrapeBatch: marshal body: %w", err)
 	}
 
-	endpoint, _ := url.Parse(c.host + "/scrape/batch")
-	endpoint.RawQuery = "key=" + url.QueryEscape(c.key)
+	endpoint, _ := url.Parse(c.endpoint("/scrape/batch"))
+	endpoint.RawQuery = "key=" + url.QueryEscape(c.APIKey())
 
 	req, err := http.NewRequest(http.MethodPost, endpoint.String(), bytes.NewReader(payload))
 	if err != nil {
@@ -145,9 +146,9 @@ func (c *Client) ScrapeBatchWithOptions(configs []*ScrapeConfig, opts BatchOptio
 	// Accept-Encoding header itself. Setting it manually opts out
 	// of auto-decompress and leaves the body as raw gzipped bytes,
 	// which would break the multipart parser downstream.
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("ScrapeBatch: http do: %w", err)
 	}
@@ -0,0 +1,67 @@
+package scrapfly
+
+import (
+	"strings"
+)
+
+// BlockSignature is a marker of a block or challenge page, see
+// ScrapeResult.IsBlocked.
+type BlockSignature struct {
+	// Reason describes the block, e.g. "cloudflare challenge".
+	Reason string
+	// Pattern is searched in the scraped content, case-insensitively.
+	Pattern string
+}
+
+// BlockSignatures are the content markers ScrapeResult.IsBlocked looks for.
+// Append to it to detect the block pages of other anti-bot vendors:
+//
+//	scrapfly.BlockSignatures = append(scrapfly.BlockSignatures,
+//	    scrapfly.BlockSignature{Reason: "acme shield", Pattern: "acme-shield-challenge"})
+var BlockSignatures = []BlockSignature{
+	{Reason: "cloudflare challenge", Pattern: "<title>Just a moment...</title>"},
+	{Reason: "cloudflare challenge", Pattern: "/cdn-cgi/challenge-platform/"},
+	{Reason: "cloudflare block", Pattern: "<title>Attention Required! | Cloudflare</title>"},
+	{Reason: "datadome captcha", Pattern: "captcha-delivery.com"},
+	{Reason: "perimeterx captcha", Pattern: "px-captcha"},
+	{Reason: "imperva block", Pattern: "_Incapsula_Resource"},
+	{Reason: "akamai block", Pattern: "You don't have permission to access"},
+	{Reason: "recaptcha", Pattern: "class=\"g-recaptcha\""},
+	{Reason: "hcaptcha", Pattern: "class=\"h-captcha\""},
+}
+
+// IsBlocked reports whether the scrape returned a block or anti-bot
+// challenge page, even though it succeeded. See BlockReason.
+//
+// Example:
+//
+//	if result.IsBlocked() {
+//	    config.ASP = true
+//	    config.ProxyPool = scrapfly.PublicResidentialPool
+//	    result, err = client.Scrape(config)
+//	}
+func (r *ScrapeResult) IsBlocked() bool {
+	return r.BlockReason() != ""
+}
+
+// BlockReason returns why the scrape looks blocked, or "" when it does not.
+//
+// The ASP context is consulted first ("asp: blocked" when Anti Scraping
+// Protection reports the target blocked the request), then text content is
+// searched for the BlockSignatures. This is a heuristic: a page merely
+// embedding a captcha widget may be reported as blocked.
+func (r *ScrapeResult) BlockReason() string {
+	if asp, err := r.Context.ASPInfo(); err == nil && asp != nil && asp.Blocked {
+		return "asp: blocked"
+	}
+	if r.rawContent || r.Result.Format == "binary" || r.IsLargeObject() {
+		return ""
+	}
+	content := strings.ToLower(r.Result.Content)
+	for _, signature := range BlockSignatures {
+		if signature.Pattern != "" && strings.Contains(content, strings.ToLower(signature.Pattern)) {
+			return signature.Reason
+		}
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+package scrapfly
+
+import (
+	"testing"
+)
+
+func TestScrapeResult_IsBlocked(t *testing.T) {
+	for content, want := range map[string]string{
+		`<html><head><title>Just a moment...</title></head><body>Checking your browser</body></html>`:       "cloudflare challenge",
+		`<html><script src="https://geo.captcha-delivery.com/captcha/"></script></html>`:                    "datadome captcha",
+		`<html><body><div id="px-captcha"></div></body></html>`:                                             "perimeterx captcha",
+		`<html><head><title>Access Denied</title></head><body>You don't have permission to access</body>`:   "akamai block",
+		`<html><head><title>Product</title></head><body><p>Just a moment, loading prices</p></body></html>`: "",
+	} {
+		r := htmlResult("https://example.com", content)
+		if got := r.BlockReason(); got != want {
+			t.Errorf("BlockReason(%q) = %q, want %q", content, got, want)
+		}
+		if r.IsBlocked() != (want != "") {
+			t.Errorf("IsBlocked(%q) = %v", content, r.IsBlocked())
+		}
+	}
+}
+
+func TestScrapeResult_IsBlockedASPContext(t *testing.T) {
+	r := htmlResult("https://example.com", "<html><body>ok</body></html>")
+	r.Context.ASP = map[string]interface{}{"blocked": true}
+	if r.BlockReason() != "asp: blocked" {
+		t.Errorf("BlockReason() = %q", r.BlockReason())
+	}
+	r.Context.ASP = map[string]interface{}{"blocked": false}
+	if r.IsBlocked() {
+		t.Error("unexpected block")
+	}
+}
+
+func TestScrapeResult_IsBlockedCustomSignature(t *testing.T) {
+	previous := BlockSignatures
+	t.Cleanup(func() { BlockSignatures = previous })
+	BlockSignatures = append(BlockSignatures, BlockSignature{Reason: "acme shield", Pattern: "ACME-SHIELD"})
+
+	r := htmlResult("https://example.com", `<html><body class="acme-shield">denied</body></html>`)
+	if r.BlockReason() != "acme shield" {
+		t.Errorf("BlockReason() = %q", r.BlockReason())
+	}
+}
@@ -49,12 +49,12 @@ func (c *Client) Classify(ctx context.Context, req *ClassifyRequest) (*ClassifyR
 		return nil, fmt.Errorf("scrapfly: marshal classify request: %w", err)
 	}
 
-	endpointURL, err := url.Parse(c.host + "/classify")
+	endpointURL, err := url.Parse(c.endpoint("/classify"))
 	if err != nil {
 		return nil, fmt.Errorf("scrapfly: parse classify url: %w", err)
 	}
 	params := url.Values{}
-	params.Set("key", c.key)
+	params.Set("key", c.APIKey())
 	endpointURL.RawQuery = params.Encode()
 
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpointURL.String(), bytes.NewReader(payload))
@@ -63,9 +63,9 @@ func (c *Client) Classify(ctx context.Context, req *ClassifyRequest) (*ClassifyR
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("User-Agent", sdkUserAgent)
+	httpReq.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, err
 	}
@@ -2,12 +2,15 @@ package scrapfly
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -24,10 +27,23 @@ const (
 // Client is the main client for interacting with the Scrapfly API.
 // It handles authentication, request execution, and response parsing.
 type Client struct {
+	keyMu            sync.RWMutex
 	key              string
 	host             string
+	pathPrefix       string
 	cloudBrowserHost string
 	httpClient       *http.Client
+	limiter          *concurrencyLimiter
+	throttler        *domainThrottler
+	maxResponseBytes int64
+	maxTotalDuration time.Duration
+	userAgentSuffix  string
+
+	requestInterceptors  []RequestInterceptor
+	responseInterceptors []ResponseInterceptor
+	tracer               Tracer
+	metrics              Metrics
+	defaultScrapeConfig  *ScrapeConfig
 }
 
 // SetCloudBrowserHost overrides the default Cloud Browser host
@@ -38,6 +54,28 @@ func (c *Client) SetCloudBrowserHost(host string) {
 	c.cloudBrowserHost = host
 }
 
+// SetPathPrefix sets a path prepended to every API endpoint, for
+// deployments serving the API under a versioned or prefixed path such as
+// "/v1". The prefix is normalized to a single leading slash and no trailing
+// slash. The default is empty: endpoints are served at the host root.
+//
+// Example:
+//
+//	client, _ := scrapfly.NewWithHost("YOUR_API_KEY", "https://scrapfly.internal.example.com", true)
+//	client.SetPathPrefix("/v1") // scrapes now go to https://scrapfly.internal.example.com/v1/scrape
+func (c *Client) SetPathPrefix(prefix string) {
+	prefix = strings.Trim(prefix, "/")
+	if prefix != "" {
+		prefix = "/" + prefix
+	}
+	c.pathPrefix = prefix
+}
+
+// endpoint returns the URL of the API endpoint at path.
+func (c *Client) endpoint(path string) string {
+	return c.host + c.pathPrefix + path
+}
+
 // SetHTTPClient replaces the underlying *http.Client used for all API calls.
 // This lets callers install a custom transport (e.g. for request logging,
 // tracing, tests, or a shared connection pool).
@@ -58,6 +96,224 @@ func (c *Client) SetHTTPClient(httpClient *http.Client) {
 	c.httpClient = httpClient
 }
 
+// SetForceHTTP1 pins the API connections to HTTP/1.1 when force is true, for
+// corporate proxies and middleboxes that mishandle HTTP/2. Passing false
+// restores HTTP/2 negotiation. It applies to the transport installed at the
+// time of the call, so call it after SetHTTPClient; it returns an error when
+// that transport is not an *http.Transport. The transport is copied, not
+// modified.
+//
+// Example:
+//
+//	client, _ := scrapfly.New("YOUR_API_KEY")
+//	if err := client.SetForceHTTP1(true); err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *Client) SetForceHTTP1(force bool) error {
+	var transport *http.Transport
+	switch t := c.httpClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return fmt.Errorf("cannot configure HTTP/1.1 on custom transport %T", t)
+	}
+	transport.ForceAttemptHTTP2 = !force
+	transport.TLSNextProto = nil
+	if force {
+		// A non-nil empty map disables the transport's HTTP/2 support, and
+		// h2 must not be offered during the TLS handshake either.
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+		if transport.TLSClientConfig != nil {
+			transport.TLSClientConfig.NextProtos = slices.DeleteFunc(slices.Clone(transport.TLSClientConfig.NextProtos), func(proto string) bool {
+				return proto == "h2"
+			})
+		}
+	}
+	httpClient := *c.httpClient
+	httpClient.Transport = transport
+	c.httpClient = &httpClient
+	return nil
+}
+
+// RequestInterceptor is called with every outbound HTTP request made by the
+// client, right before it is sent. It may modify the request, e.g. to add
+// headers. See Client.AddRequestInterceptor.
+type RequestInterceptor func(req *http.Request)
+
+// ResponseInterceptor is called with every HTTP response received by the
+// client, before the SDK reads it. It must not consume resp.Body.
+// See Client.AddResponseInterceptor.
+type ResponseInterceptor func(resp *http.Response)
+
+// AddRequestInterceptor registers a hook called before every HTTP request
+// the client sends, for logging, tracing, custom auth or auditing.
+//
+// Interceptors run in registration order, once per attempt: a request retried
+// by the SDK (network errors, 5xx responses, retryable scrape errors) calls
+// them again for each retry. Register interceptors before making requests.
+//
+// Example:
+//
+//	client.AddRequestInterceptor(func(req *http.Request) {
+//	    req.Header.Set("X-Request-Source", "price-monitor")
+//	})
+func (c *Client) AddRequestInterceptor(interceptor RequestInterceptor) {
+	if interceptor == nil {
+		return
+	}
+	c.requestInterceptors = append(c.requestInterceptors, interceptor)
+}
+
+// AddResponseInterceptor registers a hook called with every HTTP response
+// the client receives, including the 5xx responses that are retried.
+// It is not called when the request fails without a response.
+//
+// Interceptors run in registration order, once per attempt, before the SDK
+// checks the status code or reads the body.
+//
+// Example:
+//
+//	client.AddResponseInterceptor(func(resp *http.Response) {
+//	    log.Println(resp.Request.Method, resp.Request.URL.Path, resp.StatusCode)
+//	})
+func (c *Client) AddResponseInterceptor(interceptor ResponseInterceptor) {
+	if interceptor == nil {
+		return
+	}
+	c.responseInterceptors = append(c.responseInterceptors, interceptor)
+}
+
+// do sends req with the HTTP client, running the registered interceptors and
+// reporting the request to the Metrics. Every API request goes through it,
+// holding a global concurrency slot (see SetGlobalConcurrency) until the
+// response headers are received.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	for _, interceptor := range c.requestInterceptors {
+		interceptor(req)
+	}
+	release, err := c.acquireSlot(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	release()
+	if err != nil {
+		c.metricsSink().ObserveRequest(metricsEndpoint(req.URL, c.pathPrefix), 0, time.Since(start))
+		return nil, err
+	}
+	c.metricsSink().ObserveRequest(metricsEndpoint(req.URL, c.pathPrefix), resp.StatusCode, time.Since(start))
+	for _, interceptor := range c.responseInterceptors {
+		interceptor(resp)
+	}
+	return resp, nil
+}
+
+// SetUserAgent appends an application identifier to the SDK User-Agent sent
+// with every API request, e.g. "Scrapfly-Go-SDK MyApp/1.2.3". It helps
+// Scrapfly support attribute traffic. An empty string restores the default.
+//
+// Example:
+//
+//	client.SetUserAgent("MyApp/1.2.3")
+func (c *Client) SetUserAgent(userAgent string) {
+	c.userAgentSuffix = strings.TrimSpace(userAgent)
+}
+
+// userAgent returns the User-Agent header value for API requests.
+func (c *Client) userAgent() string {
+	if c.userAgentSuffix == "" {
+		return sdkUserAgent
+	}
+	return sdkUserAgent + " " + c.userAgentSuffix
+}
+
+// SetDefaultScrapeConfig sets a template merged into the config of every
+// Scrape, ScrapeProxified, ConcurrentScrape and ScrapeBatch call, for the
+// settings repeated on every scrape (ASP, Country, ProxyPool, Headers...).
+// Passing nil removes the template.
+//
+// Precedence: a field set on the per-call config wins, and a zero field
+// takes the template value. Maps (Headers, Cookies, Screenshots...) are
+// merged key by key, per-call keys winning (header names are compared
+// case-insensitively); slices are not merged, a non-empty per-call slice
+// replaces the template one. As zero values can't be told from unset
+// ones, a boolean enabled in the template (ASP, RenderJS...) can't be
+// disabled per call. The per-call config is left untouched, and so is the
+// template, which is copied.
+//
+// Example:
+//
+//	client.SetDefaultScrapeConfig(&scrapfly.ScrapeConfig{
+//	    ASP:       true,
+//	    Country:   "us",
+//	    ProxyPool: scrapfly.PublicResidentialPool,
+//	})
+//	result, err := client.Scrape(&scrapfly.ScrapeConfig{URL: "https://example.com"})
+func (c *Client) SetDefaultScrapeConfig(config *ScrapeConfig) {
+	if config == nil {
+		c.defaultScrapeConfig = nil
+		return
+	}
+	c.defaultScrapeConfig = config.Clone()
+}
+
+// SetMaxResponseBytes caps the size of the API responses the client buffers
+// for Scrape, Screenshot and Extract calls. Responses larger than n bytes are
+// not read further and fail with ErrResponseTooLarge. n <= 0 means unlimited
+// (default).
+//
+// Example:
+//
+//	client.SetMaxResponseBytes(50 << 20) // 50 MiB
+func (c *Client) SetMaxResponseBytes(n int64) {
+	c.maxResponseBytes = n
+}
+
+// SetMaxTotalDuration caps the wall-clock time of a Scrape, Screenshot or
+// Extract call, retries, backoff delays and throttling or concurrency waits
+// included. Once d has elapsed the call is abandoned and returns
+// context.DeadlineExceeded. d <= 0 means no overall limit (default).
+//
+// The http.Client timeout (150s by default, see SetHTTPClient) still bounds
+// each attempt, so without an overall limit a call retried up to 3 times
+// can take about three times that plus the delays. An earlier deadline on
+// the context passed to the *WithContext methods takes precedence. As with
+// context cancellation, this is client-side only: a scrape the API already
+// received runs to completion and is billed.
+//
+// Example:
+//
+//	client.SetMaxTotalDuration(3 * time.Minute)
+func (c *Client) SetMaxTotalDuration(d time.Duration) {
+	c.maxTotalDuration = d
+}
+
+// withMaxTotalDuration applies the SetMaxTotalDuration limit to ctx.
+func (c *Client) withMaxTotalDuration(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.maxTotalDuration <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.maxTotalDuration)
+}
+
+// readResponseBody reads resp.Body, enforcing the SetMaxResponseBytes limit.
+func (c *Client) readResponseBody(resp *http.Response) ([]byte, error) {
+	if c.maxResponseBytes <= 0 {
+		return io.ReadAll(resp.Body)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.maxResponseBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > c.maxResponseBytes {
+		return nil, fmt.Errorf("%w: response exceeds %d bytes", ErrResponseTooLarge, c.maxResponseBytes)
+	}
+	return body, nil
+}
+
 // HTTPClient returns the *http.Client used by this Scrapfly client.
 // Useful if callers want to wrap the existing transport instead of replacing it.
 func (c *Client) HTTPClient() *http.Client {
@@ -115,15 +371,52 @@ func NewWithHost(key, host string, verifySSL bool) (*Client, error) {
 
 // APIKey returns the currently configured API key.
 func (c *Client) APIKey() string {
+	c.keyMu.RLock()
+	defer c.keyMu.RUnlock()
 	return c.key
 }
 
 // SetAPIKey updates the API key for the client.
 // This is useful for switching between different API keys at runtime.
+// It is safe to call while other goroutines use the client; calls already
+// in flight keep the key they started with. To issue calls with another key
+// without affecting the shared client, use WithKey.
 func (c *Client) SetAPIKey(key string) {
+	c.keyMu.Lock()
+	defer c.keyMu.Unlock()
 	c.key = key
 }
 
+// WithKey returns a copy of the client that authenticates with key. The copy
+// shares the HTTP client, concurrency limit and domain throttle of c, so it
+// is cheap to create per call and safe to use alongside c.
+//
+// Example:
+//
+//	other := client.WithKey("OTHER_PROJECT_API_KEY")
+//	result, err := other.Scrape(&scrapfly.ScrapeConfig{URL: "https://example.com"})
+func (c *Client) WithKey(key string) *Client {
+	// Fields are copied one by one as the key mutex must not be copied.
+	return &Client{
+		key:              key,
+		host:             c.host,
+		pathPrefix:       c.pathPrefix,
+		cloudBrowserHost: c.cloudBrowserHost,
+		httpClient:       c.httpClient,
+		limiter:          c.limiter,
+		throttler:        c.throttler,
+		maxResponseBytes: c.maxResponseBytes,
+		maxTotalDuration: c.maxTotalDuration,
+		userAgentSuffix:  c.userAgentSuffix,
+
+		requestInterceptors:  slices.Clone(c.requestInterceptors),
+		responseInterceptors: slices.Clone(c.responseInterceptors),
+		tracer:               c.tracer,
+		metrics:              c.metrics,
+		defaultScrapeConfig:  c.defaultScrapeConfig,
+	}
+}
+
 // VerifyAPIKey checks if the configured API key is valid.
 // Returns a VerifyAPIKeyResult indicating whether the key is valid.
 //
@@ -137,28 +430,53 @@ func (c *Client) SetAPIKey(key string) {
 //	    fmt.Println("API key is valid")
 //	}
 func (c *Client) VerifyAPIKey() (*VerifyAPIKeyResult, error) {
-	endpointURL, _ := url.Parse(c.host + "/account")
+	valid, err := c.checkAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	return &VerifyAPIKeyResult{Valid: valid}, nil
+}
+
+// Ping checks connectivity and authentication against the API without
+// spending scrape credits, and measures the round-trip latency.
+//
+// Example:
+//
+//	ping, err := client.Ping()
+//	if err != nil {
+//	    log.Fatal(err) // network failure
+//	}
+//	fmt.Println(ping.Host, ping.Valid, ping.Latency)
+func (c *Client) Ping() (*PingResult, error) {
+	start := time.Now()
+	valid, err := c.checkAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	return &PingResult{Valid: valid, Latency: time.Since(start), Host: c.host}, nil
+}
+
+// checkAPIKey calls /account and reports whether the API accepted the key.
+func (c *Client) checkAPIKey() (bool, error) {
+	endpointURL, _ := url.Parse(c.endpoint("/account"))
 	params := url.Values{}
-	params.Set("key", c.key)
+	params.Set("key", c.APIKey())
 	endpointURL.RawQuery = params.Encode()
 
 	req, err := http.NewRequest("GET", endpointURL.String(), nil)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 	defer resp.Body.Close()
 	_, _ = io.ReadAll(resp.Body)
 
-	if resp.StatusCode == http.StatusOK {
-		return &VerifyAPIKeyResult{Valid: true}, nil
-	}
-	return &VerifyAPIKeyResult{Valid: false}, nil
+	return resp.StatusCode == http.StatusOK, nil
 }
 
 // Scrape performs a web scraping request using the provided configuration.
@@ -185,9 +503,115 @@ func (c *Client) VerifyAPIKey() (*VerifyAPIKeyResult, error) {
 //	    log.Fatal(err)
 //	}
 //	fmt.Println(result.Result.Content)
+//
+// When the scrape itself fails (the API answered but the scrape did not
+// succeed), the error is returned along with the non-nil result, so the log
+// URL, cost and any partial content remain available:
+//
+//	result, err := client.Scrape(config)
+//	if err != nil && result != nil {
+//	    log.Printf("scrape failed (%d credits), see %s", result.Context.Cost.Total, result.Result.LogURL)
+//	}
 func (c *Client) Scrape(config *ScrapeConfig) (*ScrapeResult, error) {
+	return c.ScrapeWithContext(context.Background(), config)
+}
+
+// ScrapeWithContext is like Scrape but the request, its retries and any
+// throttling or concurrency wait are abandoned as soon as ctx is done.
+// This is client-side only: the API has no scrape cancellation, a scrape it
+// already received runs to completion and is billed.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	result, err := client.ScrapeWithContext(ctx, &scrapfly.ScrapeConfig{URL: "https://example.com"})
+func (c *Client) ScrapeWithContext(ctx context.Context, config *ScrapeConfig) (result *ScrapeResult, err error) {
+	config = config.withDefaults(c.defaultScrapeConfig)
+	ctx, cancel := c.withMaxTotalDuration(ctx)
+	defer cancel()
+	ctx, span := c.startSpan(ctx, "scrapfly.Scrape")
+	span.SetAttribute("scrapfly.url", config.URL)
+	span.SetAttribute("scrapfly.render_js", config.RenderJS)
+	// Every billed attempt is reported, the blocked one of an escalated
+	// scrape included.
+	billed, costReported := 0, false
+	observeCost := func(attempt *ScrapeResult, attemptErr error) {
+		var apiErr *APIError
+		if attempt == nil && errors.As(attemptErr, &apiErr) {
+			attempt = apiErr.APIResponse
+		}
+		if attempt != nil {
+			c.metricsSink().ObserveCost(attempt.Context.Cost.Total)
+			billed += attempt.Context.Cost.Total
+			costReported = true
+		}
+	}
+	defer func() {
+		observeCost(result, err)
+		if result != nil {
+			span.SetAttribute("scrapfly.status", result.Result.Status)
+			span.SetAttribute("scrapfly.upstream_status_code", result.Result.StatusCode)
+		}
+		if costReported {
+			span.SetAttribute("scrapfly.cost", billed)
+		}
+		endSpan(span, err)
+	}()
+
+	result, err = c.scrape(ctx, config)
+	if config.EscalateProxyPool && config.ProxyPool != PublicResidentialPool && shouldEscalate(result, err) {
+		if escalated, ok := config.escalated(scrapeCost(result, err)); ok {
+			observeCost(result, err)
+			DefaultLogger.Info("scrape of", config.URL, "blocked, escalating to", PublicResidentialPool, "with ASP")
+			span.SetAttribute("scrapfly.escalated", true)
+			return c.scrape(ctx, escalated)
+		}
+		DefaultLogger.Info("scrape of", config.URL, "blocked, cost budget exhausted, not escalating")
+	}
+	return result, err
+}
+
+// shouldEscalate reports whether a scrape outcome looks like a block that a
+// residential proxy with ASP may get past, see ScrapeConfig.EscalateProxyPool.
+// Successful scrapes only count on the API's own signals, not on the
+// BlockSignatures content heuristic, as a false positive costs a billed
+// residential retry.
+func shouldEscalate(result *ScrapeResult, err error) bool {
+	if err == nil {
+		if result == nil {
+			return false
+		}
+		if asp, aspErr := result.Context.ASPInfo(); aspErr == nil && asp != nil && asp.Blocked {
+			return true
+		}
+		return result.Result.StatusCode == http.StatusForbidden || result.Result.StatusCode == http.StatusTooManyRequests
+	}
+	if errors.Is(err, ErrASPBypassFailed) || errors.Is(err, ErrProxyFailed) {
+		return true
+	}
+	return IsUpstreamForbidden(err) || IsUpstreamRateLimited(err)
+}
+
+// scrapeCost returns the API credits billed for a scrape outcome.
+func scrapeCost(result *ScrapeResult, err error) int {
+	var apiErr *APIError
+	if result == nil && errors.As(err, &apiErr) {
+		result = apiErr.APIResponse
+	}
+	if result == nil {
+		return 0
+	}
+	return result.Context.Cost.Total
+}
+
+// scrape runs a scrape, retrying the errors the API flags as retryable.
+func (c *Client) scrape(ctx context.Context, config *ScrapeConfig) (*ScrapeResult, error) {
 	DefaultLogger.Debug("scraping", "url", config.URL)
 
+	if config.ProxifiedResponse {
+		return nil, fmt.Errorf("%w: ProxifiedResponse returns the raw upstream response, use ScrapeProxified instead of Scrape", ErrScrapeConfig)
+	}
 	if err := config.processBody(); err != nil {
 		return nil, err
 	}
@@ -195,36 +619,75 @@ func (c *Client) Scrape(config *ScrapeConfig) (*ScrapeResult, error) {
 	if err != nil {
 		return nil, err
 	}
-	params.Set("key", c.key)
+	params.Set("key", c.APIKey())
 
-	endpointURL, _ := url.Parse(c.host + "/scrape")
-	endpointURL.RawQuery = params.Encode()
+	endpointURL, _ := url.Parse(c.endpoint("/scrape"))
+	endpointURL.RawQuery = config.encodeAPIParams(params)
 
 	method := "GET"
 	if config.Method != "" {
-		method = strings.ToUpper(config.Method.String())
+		method = strings.ToUpper(string(config.Method))
+	}
+
+	start := time.Now()
+	// Transport-level failures (network errors, 5xx) are retried by
+	// fetchWithRetry. On top of that, retry scrape-level errors the API
+	// itself flags as retryable (APIErrorDetails.Retryable), and give up
+	// right away on the ones it flags as not retryable.
+	for attempt := 1; ; attempt++ {
+		result, err := c.scrapeOnce(ctx, config, method, endpointURL.String())
+		if result != nil {
+			result.config = config.Clone()
+		}
+		if err == nil || attempt >= defaultRetries {
+			return result, err
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !apiErr.Retryable {
+			return result, err
+		}
+		if !retryBudgetFromContext(ctx).take() {
+			DefaultLogger.debugFields("scrape failed with retryable error, retry budget exhausted, giving up",
+				"code", apiErr.Code, "attempt", attempt, "elapsed", time.Since(start))
+			return result, err
+		}
+		delay := defaultDelay
+		if apiErr.RetryAfterMs > 0 {
+			delay = time.Duration(apiErr.RetryAfterMs) * time.Millisecond
+		}
+		DefaultLogger.debugFields("scrape failed with retryable error, retrying",
+			"code", apiErr.Code, "attempt", attempt, "max_attempts", defaultRetries,
+			"backoff", delay, "elapsed", time.Since(start))
+		c.metricsSink().ObserveRetry("/scrape")
+		if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+			return result, fmt.Errorf("%w: %w", sleepErr, err)
+		}
 	}
+}
 
-	req, err := http.NewRequest(method, endpointURL.String(), strings.NewReader(config.Body))
+// scrapeOnce sends a single prepared scrape request and parses its result.
+func (c *Client) scrapeOnce(ctx context.Context, config *ScrapeConfig, method, endpointURL string) (*ScrapeResult, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpointURL, config.requestBody())
 	if err != nil {
 		return nil, err
 	}
 	req.GetBody = func() (io.ReadCloser, error) {
-		return io.NopCloser(strings.NewReader(config.Body)), nil
-	}
-	for key, value := range config.Headers {
-		req.Header.Set(key, value)
+		return io.NopCloser(config.requestBody()), nil
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	setConfigHeaders(req, config.headerView())
+	req.Header.Set("User-Agent", c.userAgent())
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := fetchWithRetry(c.httpClient, req, defaultRetries, defaultDelay)
+	if err := c.waitForDomain(ctx, config.URL); err != nil {
+		return nil, err
+	}
+	resp, err := fetchWithRetry(c, req, defaultRetries, defaultDelay)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+	bodyBytes, err := c.readResponseBody(resp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
@@ -262,25 +725,31 @@ func (c *Client) Scrape(config *ScrapeConfig) (*ScrapeResult, error) {
 	if err := json.Unmarshal(bodyBytes, &result); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal scrape result: %w", err)
 	}
+	return c.finishScrapeResult(ctx, &result, config.DeferLargeObjects)
+}
+
+// finishScrapeResult turns a decoded scrape result into the value returned to
+// callers: large objects are fetched (unless deferred), API keys are added
+// back to screenshot and attachment URLs, and failed scrapes are converted
+// to errors. Failed scrapes are returned along with their error so callers
+// can still inspect the log URL, cost and partial content.
+func (c *Client) finishScrapeResult(ctx context.Context, result *ScrapeResult, deferLargeObjects bool) (*ScrapeResult, error) {
+	result.client = c
 	if result.Result.Success && result.Result.Status == "DONE" {
 		DefaultLogger.Debug("scrape log url:", result.Result.LogURL)
 
 		// handle large objects (clob/blob formats)
-		contentFormat := result.Result.Format
-		if contentFormat == "clob" || contentFormat == "blob" {
-			newContent, newFormat, err := c.handleLargeObjects(result.Result.Content, contentFormat)
-			if err != nil {
-				return nil, fmt.Errorf("failed to fetch large object: %w", err)
+		if result.IsLargeObject() && !deferLargeObjects {
+			if err := result.resolveLargeObject(ctx); err != nil {
+				return nil, err
 			}
-			result.Result.Content = newContent
-			result.Result.Format = newFormat
 		}
 		/////////////////////////////////////////
 
 		// Add back apiKey to screenshots URLs
 		for name, screenshot := range result.Result.Screenshots {
 			newScreenshot := Screenshot{
-				URL:         screenshot.URL + "?key=" + c.key,
+				URL:         screenshot.URL + "?key=" + c.APIKey(),
 				Extension:   screenshot.Extension,
 				Format:      screenshot.Format,
 				Size:        screenshot.Size,
@@ -293,7 +762,7 @@ func (c *Client) Scrape(config *ScrapeConfig) (*ScrapeResult, error) {
 		// Add back apiKey to attachments URLs
 		for i, attachment := range result.Result.BrowserData.Attachments {
 			newAttachment := Attachment{
-				Content:           attachment.Content + "?key=" + c.key,
+				Content:           attachment.Content + "?key=" + c.APIKey(),
 				ContentType:       attachment.ContentType,
 				Filename:          attachment.Filename,
 				ID:                attachment.ID,
@@ -306,13 +775,27 @@ func (c *Client) Scrape(config *ScrapeConfig) (*ScrapeResult, error) {
 		}
 		/////////////////////////////////////////
 
-		return &result, nil
+		return result, nil
+	}
+	return result, c.createErrorFromResult(result)
+}
+
+// setConfigHeaders copies the scrape config headers, see
+// ScrapeConfig.headerView, onto the API request. Accept-Encoding is skipped:
+// it is meant for the upstream request (it is already sent as a
+// headers[accept-encoding] param), and setting it here would disable the
+// transport's transparent decompression of the API response.
+func setConfigHeaders(req *http.Request, headers http.Header) {
+	for key, values := range headers {
+		if strings.EqualFold(key, "Accept-Encoding") {
+			continue
+		}
+		req.Header[key] = values
 	}
-	return nil, c.createErrorFromResult(&result)
 }
 
 // handleLargeObjects fetches content for large objects (clob/blob formats) using the internal API key.
-func (c *Client) handleLargeObjects(contentURL string, format string) (string, string, error) {
+func (c *Client) handleLargeObjects(ctx context.Context, contentURL string, format string) (string, string, error) {
 	parsedURL, err := url.Parse(contentURL)
 	if err != nil {
 		DefaultLogger.Error("failed to parse content URL:", err)
@@ -322,15 +805,16 @@ func (c *Client) handleLargeObjects(contentURL string, format string) (string, s
 	params.Set("key", c.APIKey())
 	parsedURL.RawQuery = params.Encode()
 
-	req, err := http.NewRequest("GET", parsedURL.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", parsedURL.String(), nil)
 	if err != nil {
 		return "", "", err
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
-	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+	// Accept-Encoding is left to the transport, which only decompresses
+	// responses transparently when it negotiated the encoding itself.
+	req.Header.Set("User-Agent", c.userAgent())
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		DefaultLogger.Error("failed to fetch large object:", err)
 		return "", "", err
@@ -344,13 +828,13 @@ func (c *Client) handleLargeObjects(contentURL string, format string) (string, s
 
 	switch format {
 	case "clob":
-		bodyBytes, err := io.ReadAll(resp.Body)
+		bodyBytes, err := c.readResponseBody(resp)
 		if err != nil {
 			return "", "", fmt.Errorf("failed to read clob response: %w", err)
 		}
 		return string(bodyBytes), "text", nil
 	case "blob":
-		bodyBytes, err := io.ReadAll(resp.Body)
+		bodyBytes, err := c.readResponseBody(resp)
 		if err != nil {
 			return "", "", fmt.Errorf("failed to read blob response: %w", err)
 		}
@@ -360,39 +844,94 @@ func (c *Client) handleLargeObjects(contentURL string, format string) (string, s
 	}
 }
 
+// ScrapeWithScreenshot scrapes config with JavaScript rendering and
+// screenshots enabled, and downloads every screenshot before returning, so
+// the HTML and the images are both available without further round trips.
+//
+// When config.Screenshots is empty a single full page screenshot named
+// "fullpage" is taken. Like Scrape, a failed scrape is returned along with
+// its error, and so is the scrape when a screenshot download fails: the
+// screenshots downloaded so far keep their image.
+//
+// Example:
+//
+//	result, err := client.ScrapeWithScreenshot(&scrapfly.ScrapeConfig{URL: "https://example.com"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	shot := result.Result.Screenshots["fullpage"]
+//	image, _ := shot.Image() // already downloaded
+func (c *Client) ScrapeWithScreenshot(config *ScrapeConfig) (*ScrapeResult, error) {
+	return c.ScrapeWithScreenshotWithContext(context.Background(), config)
+}
+
+// ScrapeWithScreenshotWithContext is like ScrapeWithScreenshot but the scrape
+// and the screenshot downloads are abandoned as soon as ctx is done. The
+// SetMaxTotalDuration limit covers both. config is not modified.
+func (c *Client) ScrapeWithScreenshotWithContext(ctx context.Context, config *ScrapeConfig) (*ScrapeResult, error) {
+	ctx, cancel := c.withMaxTotalDuration(ctx)
+	defer cancel()
+
+	config = config.Clone()
+	config.RenderJS = true
+	if len(config.Screenshots) == 0 {
+		config.Screenshots = map[string]string{"fullpage": "fullpage"}
+	}
+	result, err := c.ScrapeWithContext(ctx, config)
+	if err != nil {
+		return result, err
+	}
+	for name, screenshot := range result.Result.Screenshots {
+		if err := c.downloadScreenshot(ctx, &screenshot); err != nil {
+			return result, fmt.Errorf("failed to download screenshot %s: %w", name, err)
+		}
+		result.Result.Screenshots[name] = screenshot
+	}
+	return result, nil
+}
+
+// downloadScreenshot fetches the screenshot image with the client's HTTP
+// client and caches it on s. The screenshot URL already carries the API key.
+func (c *Client) downloadScreenshot(ctx context.Context, s *Screenshot) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.URL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", c.userAgent())
+
+	resp, err := fetchWithRetry(c, req, defaultRetries, defaultDelay)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	image, err := c.readResponseBody(resp)
+	if err != nil {
+		return fmt.Errorf("failed to read screenshot body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	s.image = image
+	return nil
+}
+
 // ConcurrentScrapeResult is one entry in the channel returned by ConcurrentScrape.
-// Exactly one of Result and Error is non-nil per emission.
+// Error is nil on success; like Scrape, a failed scrape may still carry its
+// Result.
 //
 // This was previously an anonymous struct with embedded fields, which prevented
 // callers outside package scrapfly from accessing the error (Go's universe-scope
 // `error` type produces an unexported promoted field in anonymous structs).
 // Named exported fields make the result usable from any caller.
 type ConcurrentScrapeResult struct {
-	// Result is the successful scrape, or nil when Error is set.
+	// Result is the scrape result. It is also set alongside Error when the
+	// scrape itself failed, and nil for transport or config errors.
 	Result *ScrapeResult
-	// Error is the failure, or nil when Result is set.
+	// Error is the failure, or nil on success.
 	Error error
 }
 
-// ConcurrentScrape performs multiple scraping requests concurrently with controlled concurrency.
-// This is useful for scraping multiple pages efficiently while respecting rate limits.
-//
-// Parameters:
-//   - configs: A slice of ScrapeConfig objects to scrape
-//   - concurrencyLimit: Maximum number of concurrent requests. If <= 0, uses account's concurrent limit
-//
-// Returns a channel that emits ConcurrentScrapeResult values as scrapes complete.
-// Each entry has either Result (success) or Error (failure) set.
-//
-// Example:
-//
-//	configs := []*scrapfly.ScrapeConfig{
-//	    {URL: "https://example.com/page1"},
-//	    {URL: "https://example.com/page2"},
-//	    {URL: "https://example.com/page3"},
-//	}
-//	for item := range client.ConcurrentScrape(configs, 3) {
-//
 // ScrapeProxified sends a scrape request with proxified_response=true and returns
 // the raw upstream *http.Response. The caller owns resp.Body (must Close() it).
 //
@@ -404,6 +943,12 @@ type ConcurrentScrapeResult struct {
 // Use this when you want Scrapfly to act like an HTTP proxy and your code
 // already knows how to handle raw HTTP responses.
 func (c *Client) ScrapeProxified(config *ScrapeConfig) (*http.Response, error) {
+	// Set the flag on a copy, so the config can still be used with Scrape.
+	merged := config.withDefaults(c.defaultScrapeConfig)
+	if merged == config {
+		merged = config.Clone()
+	}
+	config = merged
 	config.ProxifiedResponse = true
 
 	if err := config.processBody(); err != nil {
@@ -413,26 +958,27 @@ func (c *Client) ScrapeProxified(config *ScrapeConfig) (*http.Response, error) {
 	if err != nil {
 		return nil, err
 	}
-	params.Set("key", c.key)
+	params.Set("key", c.APIKey())
 
-	endpointURL, _ := url.Parse(c.host + "/scrape")
-	endpointURL.RawQuery = params.Encode()
+	endpointURL, _ := url.Parse(c.endpoint("/scrape"))
+	endpointURL.RawQuery = config.encodeAPIParams(params)
 
 	method := "GET"
 	if config.Method != "" {
-		method = strings.ToUpper(config.Method.String())
+		method = strings.ToUpper(string(config.Method))
 	}
 
-	req, err := http.NewRequest(method, endpointURL.String(), strings.NewReader(config.Body))
+	req, err := http.NewRequest(method, endpointURL.String(), config.requestBody())
 	if err != nil {
 		return nil, err
 	}
-	for key, value := range config.Headers {
-		req.Header.Set(key, value)
-	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	setConfigHeaders(req, config.headerView())
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	if err := c.waitForDomain(context.Background(), config.URL); err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -461,6 +1007,85 @@ func (c *Client) ScrapeProxified(config *ScrapeConfig) (*http.Response, error) {
 	return resp, nil
 }
 
+// ResultMeta is the scrape metadata returned by ScrapeStream alongside the
+// content reader.
+type ResultMeta struct {
+	// StatusCode is the HTTP status of the target website.
+	StatusCode int
+	// Header holds the upstream response headers and the X-Scrapfly-*
+	// metadata headers.
+	Header http.Header
+	// ContentType is the upstream content type.
+	ContentType string
+	// ContentFormat is the format of the content, e.g. "raw" or "markdown"
+	// (X-Scrapfly-Content-Format).
+	ContentFormat string
+	// Log identifies the scrape log in the dashboard (X-Scrapfly-Log).
+	Log string
+	// Cost is the API credits billed, or 0 when not reported.
+	Cost int
+}
+
+// ScrapeStream scrapes config and returns the page content as a stream,
+// available as soon as the first bytes arrive, instead of buffering it
+// like Scrape. Use it for very large pages or downloads that are processed
+// incrementally. The caller must Close the reader.
+//
+// The content is streamed through the proxified response mode (see
+// ScrapeProxified), so there is no ScrapeResult: helpers needing the full
+// content (Selector, Links, Title, ContentHash, IsBlocked...) and the
+// context data (ASP, cost breakdown, proxy...) are not available. Scrape
+// errors are still returned as *APIError before any content is read.
+//
+// Example:
+//
+//	body, meta, err := client.ScrapeStream(&scrapfly.ScrapeConfig{URL: "https://example.com/dump.jsonl"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer body.Close()
+//	fmt.Println("upstream status", meta.StatusCode)
+//	scanner := bufio.NewScanner(body)
+//	for scanner.Scan() {
+//	    process(scanner.Bytes())
+//	}
+func (c *Client) ScrapeStream(config *ScrapeConfig) (io.ReadCloser, *ResultMeta, error) {
+	resp, err := c.ScrapeProxified(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	meta := &ResultMeta{
+		StatusCode:    resp.StatusCode,
+		Header:        resp.Header,
+		ContentType:   resp.Header.Get("Content-Type"),
+		ContentFormat: resp.Header.Get("X-Scrapfly-Content-Format"),
+		Log:           resp.Header.Get("X-Scrapfly-Log"),
+	}
+	if cost, ok := apiCost(resp); ok {
+		meta.Cost = cost
+	}
+	return resp.Body, meta, nil
+}
+
+// ConcurrentScrape performs multiple scraping requests concurrently with controlled concurrency.
+// This is useful for scraping multiple pages efficiently while respecting rate limits.
+//
+// Parameters:
+//   - configs: A slice of ScrapeConfig objects to scrape
+//   - concurrencyLimit: Maximum number of concurrent requests. If <= 0, uses account's concurrent limit
+//
+// Returns a channel that emits ConcurrentScrapeResult values as scrapes complete.
+// Error is set for failed entries; Result is set on success and, alongside
+// Error, when the scrape itself failed, see ConcurrentScrapeResult.
+//
+// Example:
+//
+//	configs := []*scrapfly.ScrapeConfig{
+//	    {URL: "https://example.com/page1"},
+//	    {URL: "https://example.com/page2"},
+//	    {URL: "https://example.com/page3"},
+//	}
+//	for item := range client.ConcurrentScrape(configs, 3) {
 //	    if item.Error != nil {
 //	        log.Printf("Error: %v", item.Error)
 //	        continue
@@ -468,6 +1093,85 @@ func (c *Client) ScrapeProxified(config *ScrapeConfig) (*http.Response, error) {
 //	    fmt.Println(item.Result.Result.Content)
 //	}
 func (c *Client) ConcurrentScrape(configs []*ScrapeConfig, concurrencyLimit int) <-chan ConcurrentScrapeResult {
+	return c.ConcurrentScrapeWithContext(context.Background(), configs, concurrencyLimit, ConcurrentScrapeOptions{})
+}
+
+// ConcurrentScrapeOptions tunes ConcurrentScrapeWithContext.
+type ConcurrentScrapeOptions struct {
+	// FailFast cancels the whole batch on the first failed scrape: queued
+	// configs are skipped and in-flight scrapes are cancelled.
+	FailFast bool
+
+	// CorrelationID is assigned to every config of the batch that has no
+	// CorrelationID of its own, so the whole batch can be traced in the
+	// dashboard. When empty and GenerateCorrelationID is set, a random UUID
+	// is used instead.
+	CorrelationID string
+	// GenerateCorrelationID generates the batch CorrelationID when none is
+	// provided.
+	GenerateCorrelationID bool
+	// PerItemCorrelationID derives a distinct ID for each config from the
+	// batch CorrelationID by appending the config's index ("<id>-3").
+	PerItemCorrelationID bool
+
+	// RetryBudget caps the total number of retries (on network errors, 5xx
+	// responses and retryable scrape errors) across the whole batch. Once
+	// it is spent, failing scrapes return their error right away instead of
+	// retrying, so a degraded API doesn't get a retry storm. 0 means no
+	// batch-wide limit, each scrape retrying on its own.
+	RetryBudget int
+}
+
+// correlationID returns the batch correlation ID configured by opts, or ""
+// when IDs should not be assigned.
+func (opts ConcurrentScrapeOptions) correlationID() (string, error) {
+	if opts.CorrelationID != "" || !opts.GenerateCorrelationID {
+		return opts.CorrelationID, nil
+	}
+	return newUUID()
+}
+
+// withCorrelationID returns config with the batch correlation ID assigned,
+// copying it so the caller's config is left untouched. Configs that already
+// carry a CorrelationID are returned as is.
+func withCorrelationID(config *ScrapeConfig, batchID string, index int, perItem bool) *ScrapeConfig {
+	if config == nil || batchID == "" || config.CorrelationID != "" {
+		return config
+	}
+	tagged := *config
+	tagged.CorrelationID = batchID
+	if perItem {
+		tagged.CorrelationID = fmt.Sprintf("%s-%d", batchID, index)
+	}
+	return &tagged
+}
+
+// ConcurrentScrapeWithContext is like ConcurrentScrape but stops the batch
+// when ctx is done, or on the first error when opts.FailFast is set.
+//
+// Once the batch is stopped, configs that were not started yet are skipped
+// and emit nothing; scrapes in flight are cancelled and emit their error
+// (usually context.Canceled). The channel is closed when every worker has
+// returned.
+//
+// Set opts.CorrelationID or opts.GenerateCorrelationID to tag every config
+// of the batch that has no CorrelationID with a shared (or, with
+// opts.PerItemCorrelationID, a derived) ID. The configs are copied, not
+// modified.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+//	defer cancel()
+//	opts := scrapfly.ConcurrentScrapeOptions{FailFast: true}
+//	for item := range client.ConcurrentScrapeWithContext(ctx, configs, 3, opts) {
+//	    if item.Error != nil {
+//	        log.Printf("batch stopped: %v", item.Error)
+//	        continue
+//	    }
+//	    fmt.Println(item.Result.Result.Content)
+//	}
+func (c *Client) ConcurrentScrapeWithContext(ctx context.Context, configs []*ScrapeConfig, concurrencyLimit int, opts ConcurrentScrapeOptions) <-chan ConcurrentScrapeResult {
 	resultsChan := make(chan ConcurrentScrapeResult, len(configs))
 
 	var wg sync.WaitGroup
@@ -486,25 +1190,44 @@ func (c *Client) ConcurrentScrape(configs []*ScrapeConfig, concurrencyLimit int)
 		DefaultLogger.Info("concurrency not provided - setting it to", concurrencyLimit, "from account info")
 	}
 
+	batchID, err := opts.correlationID()
+	if err != nil {
+		resultsChan <- ConcurrentScrapeResult{Error: fmt.Errorf("failed to generate correlation ID: %w", err)}
+		close(resultsChan)
+		return resultsChan
+	}
+
+	if opts.RetryBudget > 0 {
+		ctx = withRetryBudget(ctx, newRetryBudget(opts.RetryBudget))
+	}
+	ctx, cancel := context.WithCancel(ctx)
+
 	jobs := make(chan *ScrapeConfig, len(configs))
 	for i := 0; i < concurrencyLimit; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			for config := range jobs {
-				result, err := c.Scrape(config)
+				if ctx.Err() != nil {
+					continue // batch stopped, drain the remaining jobs
+				}
+				result, err := c.ScrapeWithContext(ctx, config)
 				resultsChan <- ConcurrentScrapeResult{Result: result, Error: err}
+				if err != nil && opts.FailFast {
+					cancel()
+				}
 			}
 		}()
 	}
 
-	for _, config := range configs {
-		jobs <- config
+	for i, config := range configs {
+		jobs <- withCorrelationID(config, batchID, i, opts.PerItemCorrelationID)
 	}
 	close(jobs)
 
 	go func() {
 		wg.Wait()
+		cancel()
 		close(resultsChan)
 	}()
 
@@ -534,43 +1257,89 @@ func (c *Client) ConcurrentScrape(configs []*ScrapeConfig, concurrencyLimit int)
 //	}
 //	// result.Image contains the screenshot bytes
 func (c *Client) Screenshot(config *ScreenshotConfig) (*ScreenshotResult, error) {
+	return c.ScreenshotWithContext(context.Background(), config)
+}
+
+// ScreenshotWithContext is like Screenshot but aborts when ctx is done.
+func (c *Client) ScreenshotWithContext(ctx context.Context, config *ScreenshotConfig) (_ *ScreenshotResult, err error) {
+	ctx, cancel := c.withMaxTotalDuration(ctx)
+	defer cancel()
+	ctx, span := c.startSpan(ctx, "scrapfly.Screenshot")
+	span.SetAttribute("scrapfly.url", config.URL)
+	defer func() { endSpan(span, err) }()
+
 	params, err := config.toAPIParams()
 	if err != nil {
 		return nil, err
 	}
-	params.Set("key", c.key)
+	params.Set("key", c.APIKey())
 
-	endpointURL, _ := url.Parse(c.host + "/screenshot")
+	endpointURL, _ := url.Parse(c.endpoint("/screenshot"))
 	endpointURL.RawQuery = params.Encode()
 
-	req, err := http.NewRequest("GET", endpointURL.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpointURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := fetchWithRetry(c.httpClient, req, defaultRetries, defaultDelay)
+	if err := c.waitForDomain(ctx, config.URL); err != nil {
+		return nil, err
+	}
+	resp, err := fetchWithRetry(c, req, defaultRetries, defaultDelay)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	span.SetAttribute("scrapfly.status_code", resp.StatusCode)
+	if upstream, err := strconv.Atoi(resp.Header.Get("x-scrapfly-upstream-http-code")); err == nil {
+		span.SetAttribute("scrapfly.upstream_status_code", upstream)
+	}
+	if cost, ok := apiCost(resp); ok {
+		span.SetAttribute("scrapfly.cost", cost)
+		c.metricsSink().ObserveCost(cost)
+	}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+	bodyBytes, err := c.readResponseBody(resp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, c.handleAPIErrorResponse(resp, bodyBytes)
+		return nil, fmt.Errorf("%w: %w", ErrScreenshotAPIFailed, c.handleAPIErrorResponse(resp, bodyBytes))
 	}
 
 	return newScreenshotResult(resp, bodyBytes)
 }
 
+// RenderPDF renders a web page with a headless browser and returns it as a PDF.
+//
+// Example:
+//
+//	pdf, err := client.RenderPDF(&scrapfly.PDFConfig{URL: "https://example.com"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	path, _ := pdf.Save("example")
+func (c *Client) RenderPDF(config *PDFConfig) (*PDFResult, error) {
+	result, err := c.Screenshot(config.toScreenshotConfig())
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(result.Image, []byte("%PDF-")) {
+		return nil, fmt.Errorf("%w: response is not a PDF document", ErrScreenshotAPIFailed)
+	}
+	result.Metadata.ExtensionName = "pdf"
+	return &PDFResult{Data: result.Image, Metadata: result.Metadata}, nil
+}
+
 // Extract performs AI-powered structured data extraction from HTML content.
 //
 // This method uses Scrapfly's AI extraction capabilities to parse HTML and
 // extract structured data based on templates or prompts.
 //
+// When config.Webhook is set the extraction may run asynchronously: the
+// result then carries no Data, only the job UUID to pass to WaitForExtraction.
+//
 // Example:
 //
 //	config := &scrapfly.ExtractionConfig{
@@ -584,41 +1353,59 @@ func (c *Client) Screenshot(config *ScreenshotConfig) (*ScreenshotResult, error)
 //	}
 //	fmt.Printf("Extracted data: %+v\n", result.Data)
 func (c *Client) Extract(config *ExtractionConfig) (*ExtractionResult, error) {
+	return c.ExtractWithContext(context.Background(), config)
+}
+
+// ExtractWithContext is like Extract but aborts when ctx is done.
+func (c *Client) ExtractWithContext(ctx context.Context, config *ExtractionConfig) (_ *ExtractionResult, err error) {
+	ctx, cancel := c.withMaxTotalDuration(ctx)
+	defer cancel()
+	ctx, span := c.startSpan(ctx, "scrapfly.Extract")
+	span.SetAttribute("scrapfly.url", config.URL)
+	defer func() { endSpan(span, err) }()
+
 	params, err := config.toAPIParams()
 	if err != nil {
 		return nil, err
 	}
-	params.Set("key", c.key)
+	params.Set("key", c.APIKey())
 
-	endpointURL, _ := url.Parse(c.host + "/extraction")
+	endpointURL, _ := url.Parse(c.endpoint("/extraction"))
 	endpointURL.RawQuery = params.Encode()
 
-	req, err := http.NewRequest("POST", endpointURL.String(), bytes.NewReader(config.Body))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpointURL.String(), bytes.NewReader(config.Body))
 	if err != nil {
 		return nil, err
 	}
 	req.GetBody = func() (io.ReadCloser, error) {
 		return io.NopCloser(bytes.NewReader(config.Body)), nil
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 	req.Header.Set("Content-Type", config.ContentType)
 	req.Header.Set("Accept", "application/json")
 	if config.DocumentCompressionFormat != "" {
 		req.Header.Set("Content-Encoding", string(config.DocumentCompressionFormat))
 	}
 
-	resp, err := fetchWithRetry(c.httpClient, req, defaultRetries, defaultDelay)
+	resp, err := fetchWithRetry(c, req, defaultRetries, defaultDelay)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	span.SetAttribute("scrapfly.status_code", resp.StatusCode)
+	if cost, ok := apiCost(resp); ok {
+		span.SetAttribute("scrapfly.cost", cost)
+		c.metricsSink().ObserveCost(cost)
+	}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+	bodyBytes, err := c.readResponseBody(resp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, c.handleAPIErrorResponse(resp, bodyBytes)
+	// Extractions submitted with a webhook are accepted and run
+	// asynchronously; the body then only carries the job UUID and status.
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return nil, fmt.Errorf("%w: %w", ErrExtractionAPIFailed, c.handleAPIErrorResponse(resp, bodyBytes))
 	}
 
 	var result ExtractionResult
@@ -628,6 +1415,95 @@ func (c *Client) Extract(config *ExtractionConfig) (*ExtractionResult, error) {
 	return &result, nil
 }
 
+// ConcurrentExtractResult is one entry in the channel returned by
+// ConcurrentExtract. Exactly one of Result and Error is non-nil per emission.
+type ConcurrentExtractResult struct {
+	// Config is the extraction this entry reports on; results are emitted
+	// in completion order, not in the order of the configs.
+	Config *ExtractionConfig
+	// Result is the successful extraction, or nil when Error is set.
+	Result *ExtractionResult
+	// Error is the failure, or nil when Result is set.
+	Error error
+}
+
+// ConcurrentExtract runs multiple extractions concurrently, using the same
+// worker pool as ConcurrentScrape. It is useful to extract data from many
+// stored documents at once.
+//
+// Parameters:
+//   - configs: A slice of ExtractionConfig objects to extract
+//   - concurrencyLimit: Maximum number of concurrent requests. If <= 0, uses account's concurrent limit
+//
+// Calls also count against the client's global concurrency limit, see
+// SetGlobalConcurrency.
+//
+// Example:
+//
+//	configs := make([]*scrapfly.ExtractionConfig, len(pages))
+//	for i, page := range pages {
+//	    configs[i] = &scrapfly.ExtractionConfig{Body: page, ContentType: "text/html", ExtractionModel: scrapfly.ExtractionModelProduct}
+//	}
+//	for item := range client.ConcurrentExtract(configs, 3) {
+//	    if item.Error != nil {
+//	        log.Printf("Error: %v", item.Error)
+//	        continue
+//	    }
+//	    fmt.Println(item.Result.Data)
+//	}
+func (c *Client) ConcurrentExtract(configs []*ExtractionConfig, concurrencyLimit int) <-chan ConcurrentExtractResult {
+	return c.ConcurrentExtractWithContext(context.Background(), configs, concurrencyLimit)
+}
+
+// ConcurrentExtractWithContext is like ConcurrentExtract but stops the batch
+// when ctx is done: configs that were not started yet are skipped and emit
+// nothing, and extractions in flight are cancelled and emit their error.
+func (c *Client) ConcurrentExtractWithContext(ctx context.Context, configs []*ExtractionConfig, concurrencyLimit int) <-chan ConcurrentExtractResult {
+	resultsChan := make(chan ConcurrentExtractResult, len(configs))
+
+	var wg sync.WaitGroup
+
+	if concurrencyLimit <= 0 {
+		account, err := c.Account()
+		if err != nil {
+			resultsChan <- ConcurrentExtractResult{
+				Error: fmt.Errorf("failed to get account for concurrency limit: %w", err),
+			}
+			close(resultsChan)
+			return resultsChan
+		}
+		concurrencyLimit = account.Subscription.Usage.Scrape.ConcurrentLimit
+		DefaultLogger.Info("concurrency not provided - setting it to", concurrencyLimit, "from account info")
+	}
+
+	jobs := make(chan *ExtractionConfig, len(configs))
+	for i := 0; i < concurrencyLimit; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for config := range jobs {
+				if ctx.Err() != nil {
+					continue // batch stopped, drain the remaining jobs
+				}
+				result, err := c.ExtractWithContext(ctx, config)
+				resultsChan <- ConcurrentExtractResult{Config: config, Result: result, Error: err}
+			}
+		}()
+	}
+
+	for _, config := range configs {
+		jobs <- config
+	}
+	close(jobs)
+
+	go func() {
+		wg.Wait()
+		close(resultsChan)
+	}()
+
+	return resultsChan
+}
+
 // Account retrieves information about the current Scrapfly account.
 //
 // Returns account details including:
@@ -645,18 +1521,22 @@ func (c *Client) Extract(config *ExtractionConfig) (*ExtractionResult, error) {
 //	fmt.Printf("Plan: %s\n", account.Subscription.PlanName)
 //	fmt.Printf("Remaining requests: %d\n", account.Subscription.Usage.Scrape.Remaining)
 func (c *Client) Account() (*AccountData, error) {
-	endpointURL, _ := url.Parse(c.host + "/account")
+	return c.account(context.Background())
+}
+
+func (c *Client) account(ctx context.Context) (*AccountData, error) {
+	endpointURL, _ := url.Parse(c.endpoint("/account"))
 	params := url.Values{}
-	params.Set("key", c.key)
+	params.Set("key", c.APIKey())
 	endpointURL.RawQuery = params.Encode()
 
-	req, err := http.NewRequest("GET", endpointURL.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpointURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -691,10 +1571,14 @@ func (c *Client) handleAPIErrorResponse(resp *http.Response, body []byte) error
 				apiErr.Message = result.Result.Error.Message
 				apiErr.Code = result.Result.Error.Code
 				apiErr.DocumentationURL = result.Result.Error.DocURL
+				apiErr.Retryable = result.Result.Error.Retryable
 			} else {
 				apiErr.Message = "scrape failed with status: " + result.Result.Status
 				apiErr.Code = result.Result.Status
 			}
+			if isQuotaExhausted(statusCode, apiErr) {
+				return fmt.Errorf("%w: %w", ErrQuotaLimitReached, apiErr)
+			}
 			return apiErr
 		}
 	}
@@ -729,6 +1613,10 @@ func (c *Client) handleAPIErrorResponse(resp *http.Response, body []byte) error
 	case http.StatusUnauthorized:
 		apiErr.Hint = "Provide a valid API key via ?key=... or Bearer token (cloud mode)."
 	case http.StatusTooManyRequests:
+		if isQuotaExhausted(statusCode, apiErr) {
+			apiErr.Hint = "The account quota is exhausted, retrying won't help until it is renewed."
+			return fmt.Errorf("%w: %w", ErrQuotaLimitReached, apiErr)
+		}
 		apiErr.Hint = "Back off and retry after the indicated delay, or reduce concurrency/scope."
 	case http.StatusUnprocessableEntity:
 		if strings.Contains(string(body), "SCREENSHOT") {
@@ -742,6 +1630,25 @@ func (c *Client) handleAPIErrorResponse(resp *http.Response, body []byte) error
 	return apiErr
 }
 
+// quotaErrorCodes are the API error codes, beyond the per-product
+// ERR::<PRODUCT>::QUOTA_LIMIT_REACHED ones, that mean no more credits can be
+// spent until the quota or budget is renewed.
+var quotaErrorCodes = map[string]bool{
+	"ERR::THROTTLE::MAX_API_CREDIT_BUDGET_EXCEEDED": true,
+	"ERR::ACCOUNT::PAYMENT_REQUIRED":                true,
+}
+
+// isQuotaExhausted tells monthly quota exhaustion apart from rate-limit
+// throttling: both come back as 429, only the API error code tells them
+// apart.
+func isQuotaExhausted(statusCode int, apiErr *APIError) bool {
+	if statusCode != http.StatusTooManyRequests {
+		return false
+	}
+	return quotaErrorCodes[apiErr.Code] ||
+		(strings.HasPrefix(apiErr.Code, "ERR::") && strings.HasSuffix(apiErr.Code, "::QUOTA_LIMIT_REACHED"))
+}
+
 func (c *Client) createErrorFromResult(result *ScrapeResult) error {
 	apiErr := &APIError{
 		APIResponse:    result,
@@ -751,6 +1658,7 @@ func (c *Client) createErrorFromResult(result *ScrapeResult) error {
 		apiErr.Message = result.Result.Error.Message
 		apiErr.Code = result.Result.Error.Code
 		apiErr.DocumentationURL = result.Result.Error.DocURL
+		apiErr.Retryable = result.Result.Error.Retryable
 	} else {
 		apiErr.Message = "scrape failed with status: " + result.Result.Status
 		apiErr.Code = result.Result.Status
@@ -758,10 +1666,10 @@ func (c *Client) createErrorFromResult(result *ScrapeResult) error {
 
 	if !result.Result.Success {
 		if result.Result.StatusCode >= 400 && result.Result.StatusCode < 500 {
-			return fmt.Errorf("%w: %s", ErrUpstreamClient, apiErr)
+			return fmt.Errorf("%w: %w", ErrUpstreamClient, apiErr)
 		}
 		if result.Result.StatusCode >= 500 {
-			return fmt.Errorf("%w: %s", ErrUpstreamServer, apiErr)
+			return fmt.Errorf("%w: %w", ErrUpstreamServer, apiErr)
 		}
 	}
 
@@ -769,18 +1677,18 @@ func (c *Client) createErrorFromResult(result *ScrapeResult) error {
 		resource := parts[1]
 		switch resource {
 		case "SCRAPE":
-			return fmt.Errorf("%w: %s", ErrScrapeFailed, apiErr)
+			return fmt.Errorf("%w: %w", ErrScrapeFailed, apiErr)
 		case "PROXY":
-			return fmt.Errorf("%w: %s", ErrProxyFailed, apiErr)
+			return fmt.Errorf("%w: %w", ErrProxyFailed, apiErr)
 		case "ASP":
-			return fmt.Errorf("%w: %s", ErrASPBypassFailed, apiErr)
+			return fmt.Errorf("%w: %w", ErrASPBypassFailed, apiErr)
 		case "SCHEDULE":
-			return fmt.Errorf("%w: %s", ErrScheduleFailed, apiErr)
+			return fmt.Errorf("%w: %w", ErrScheduleFailed, apiErr)
 		case "WEBHOOK":
-			return fmt.Errorf("%w: %s", ErrWebhookFailed, apiErr)
+			return fmt.Errorf("%w: %w", ErrWebhookFailed, apiErr)
 		case "SESSION":
-			return fmt.Errorf("%w: %s", ErrSessionFailed, apiErr)
+			return fmt.Errorf("%w: %w", ErrSessionFailed, apiErr)
 		}
 	}
-	return fmt.Errorf("%w: %s", ErrUnhandledAPIResponse, apiErr)
+	return fmt.Errorf("%w: %w", ErrUnhandledAPIResponse, apiErr)
 }
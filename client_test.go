@@ -0,0 +1,1337 @@
+package scrapfly
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func scrapeErrorResponse(code string, retryable bool) string {
+	return fmt.Sprintf(`{"result": {"success": false, "status": "%s", "status_code": 200, "error": {"code": "%s", "message": "failed", "retryable": %t}}}`, code, code, retryable)
+}
+
+func TestClient_ScrapeRetriesRetryableError(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&calls, 1) == 1 {
+			_, _ = w.Write([]byte(scrapeErrorResponse("ERR::PROXY::TIMEOUT", true)))
+			return
+		}
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+
+	result, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Result.Content != "ok" {
+		t.Errorf("content = %q", result.Result.Content)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestClient_ScrapeCancelledDuringBackoffReturnsFailedResult(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeErrorResponse("ERR::PROXY::TIMEOUT", true)))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	result, err := client.ScrapeWithContext(ctx, &ScrapeConfig{URL: "https://example.com"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != "ERR::PROXY::TIMEOUT" {
+		t.Errorf("expected the attempt's APIError to be kept, got %v", err)
+	}
+	if result == nil {
+		t.Error("expected the failed attempt's result")
+	}
+}
+
+func TestClient_ScrapeDoesNotRetryNonRetryableError(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeErrorResponse("ERR::ASP::SHIELD_PROTECTION_FAILED", false)))
+	})
+
+	_, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"})
+	if !errors.Is(err, ErrASPBypassFailed) {
+		t.Fatalf("expected ErrASPBypassFailed, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Retryable {
+		t.Errorf("expected non-retryable *APIError, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestClient_ConcurrentScrapeFailFastSkipsQueued(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeErrorResponse("ERR::ASP::SHIELD_PROTECTION_FAILED", false)))
+	})
+
+	configs := make([]*ScrapeConfig, 5)
+	for i := range configs {
+		configs[i] = &ScrapeConfig{URL: fmt.Sprintf("https://example.com/%d", i)}
+	}
+	var results []ConcurrentScrapeResult
+	for item := range client.ConcurrentScrapeWithContext(context.Background(), configs, 1, ConcurrentScrapeOptions{FailFast: true}) {
+		results = append(results, item)
+	}
+	if len(results) != 1 || results[0].Error == nil {
+		t.Fatalf("expected a single failed result, got %+v", results)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestClient_ConcurrentScrapeCorrelationID(t *testing.T) {
+	var mu sync.Mutex
+	ids := map[string]string{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ids[r.URL.Query().Get("url")] = r.URL.Query().Get("correlation_id")
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+
+	configs := []*ScrapeConfig{
+		{URL: "https://example.com/0"},
+		{URL: "https://example.com/1", CorrelationID: "mine"},
+		{URL: "https://example.com/2"},
+	}
+	opts := ConcurrentScrapeOptions{GenerateCorrelationID: true, PerItemCorrelationID: true}
+	for item := range client.ConcurrentScrapeWithContext(context.Background(), configs, 2, opts) {
+		if item.Error != nil {
+			t.Fatal(item.Error)
+		}
+	}
+
+	batchID, ok := strings.CutSuffix(ids["https://example.com/0"], "-0")
+	if !ok || len(batchID) != 36 {
+		t.Fatalf("unexpected generated correlation ID %q", ids["https://example.com/0"])
+	}
+	if got := ids["https://example.com/2"]; got != batchID+"-2" {
+		t.Errorf("correlation_id = %q, want %q", got, batchID+"-2")
+	}
+	if got := ids["https://example.com/1"]; got != "mine" {
+		t.Errorf("explicit correlation_id was overridden with %q", got)
+	}
+	if configs[0].CorrelationID != "" {
+		t.Errorf("caller config was modified: %q", configs[0].CorrelationID)
+	}
+}
+
+func TestClient_ConcurrentScrapeRetryBudget(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	configs := make([]*ScrapeConfig, 5)
+	for i := range configs {
+		configs[i] = &ScrapeConfig{URL: fmt.Sprintf("https://example.com/%d", i)}
+	}
+	var failed int
+	for item := range client.ConcurrentScrapeWithContext(context.Background(), configs, 1, ConcurrentScrapeOptions{RetryBudget: 2}) {
+		if item.Error == nil {
+			t.Fatal("expected the scrape to fail")
+		}
+		failed++
+	}
+	if failed != len(configs) {
+		t.Errorf("failed = %d, want %d", failed, len(configs))
+	}
+	if want := int32(len(configs) + 2); calls != want {
+		t.Errorf("calls = %d, want %d (one per config plus the retry budget)", calls, want)
+	}
+}
+
+func TestClient_ConcurrentScrapeFailFastCancelsInFlight(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Query().Get("url"), "slow") {
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte(scrapeErrorResponse("ERR::ASP::SHIELD_PROTECTION_FAILED", false)))
+	})
+
+	configs := []*ScrapeConfig{
+		{URL: "https://example.com/slow"},
+		{URL: "https://example.com/fail"},
+	}
+	done := make(chan []ConcurrentScrapeResult)
+	go func() {
+		var results []ConcurrentScrapeResult
+		for item := range client.ConcurrentScrapeWithContext(context.Background(), configs, 2, ConcurrentScrapeOptions{FailFast: true}) {
+			results = append(results, item)
+		}
+		done <- results
+	}()
+
+	select {
+	case results := <-done:
+		if len(results) != 2 {
+			t.Fatalf("expected 2 results, got %d", len(results))
+		}
+		var canceled bool
+		for _, item := range results {
+			if errors.Is(item.Error, context.Canceled) {
+				canceled = true
+			}
+		}
+		if !canceled {
+			t.Errorf("expected the in-flight scrape to be canceled, got %+v", results)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("in-flight scrape was not canceled")
+	}
+}
+
+func TestClient_Ping(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/account" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("key") != "__API_KEY__" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	ping, err := client.Ping()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ping.Valid || ping.Latency <= 0 || ping.Host != client.host {
+		t.Errorf("unexpected ping result %+v", ping)
+	}
+
+	client.SetAPIKey("wrong")
+	ping, err = client.Ping()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ping.Valid {
+		t.Error("expected invalid key")
+	}
+}
+
+func TestClient_ScrapeReturnsResultOnScrapeFailure(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"context": {"cost": {"total": 26}}, "result": {"success": false, "status": "ERR::ASP::SHIELD_PROTECTION_FAILED", "status_code": 200, "log_url": "https://scrapfly.io/dashboard/monitoring/log/abc", "content": "<html>challenge</html>", "error": {"code": "ERR::ASP::SHIELD_PROTECTION_FAILED", "message": "failed", "retryable": false}}}`))
+	})
+
+	result, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"})
+	if !errors.Is(err, ErrASPBypassFailed) {
+		t.Fatalf("expected ErrASPBypassFailed, got %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected the failed scrape result to be returned")
+	}
+	if result.Result.LogURL != "https://scrapfly.io/dashboard/monitoring/log/abc" || result.Context.Cost.Total != 26 {
+		t.Errorf("unexpected result log url %q, cost %d", result.Result.LogURL, result.Context.Cost.Total)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.APIResponse != result {
+		t.Errorf("APIError.APIResponse should be the returned result")
+	}
+}
+
+func TestClient_ScrapeQuotaExhausted(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"code": "ERR::SCRAPE::QUOTA_LIMIT_REACHED", "message": "Your plan quota is exhausted", "http_code": 429}`))
+	})
+
+	_, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"})
+	if !errors.Is(err, ErrQuotaLimitReached) {
+		t.Fatalf("expected ErrQuotaLimitReached, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected wrapped 429 *APIError, got %v", err)
+	}
+}
+
+func TestClient_ScrapeThrottledIsNotQuotaExhausted(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"code": "ERR::THROTTLE::MAX_CONCURRENT_REQUEST_EXCEEDED", "message": "Too many concurrent requests", "http_code": 429}`))
+	})
+
+	_, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"})
+	if err == nil || errors.Is(err, ErrQuotaLimitReached) {
+		t.Fatalf("expected a plain rate-limit error, got %v", err)
+	}
+}
+
+func TestIsQuotaExhausted(t *testing.T) {
+	for code, want := range map[string]bool{
+		"ERR::SCRAPE::QUOTA_LIMIT_REACHED":               true,
+		"ERR::SCREENSHOT::QUOTA_LIMIT_REACHED":           true,
+		"ERR::THROTTLE::MAX_API_CREDIT_BUDGET_EXCEEDED":  true,
+		"ERR::ACCOUNT::PAYMENT_REQUIRED":                 true,
+		"ERR::THROTTLE::MAX_CONCURRENT_REQUEST_EXCEEDED": false,
+		"ERR::THROTTLE::MAX_REQUEST_RATE_EXCEEDED":       false,
+		"": false,
+	} {
+		apiErr := &APIError{Code: code, Message: "quota of concurrent requests exceeded"}
+		if got := isQuotaExhausted(http.StatusTooManyRequests, apiErr); got != want {
+			t.Errorf("%q: isQuotaExhausted = %v, want %v", code, got, want)
+		}
+	}
+	if isQuotaExhausted(http.StatusBadRequest, &APIError{Code: "ERR::SCRAPE::QUOTA_LIMIT_REACHED"}) {
+		t.Error("only 429 responses report quota exhaustion")
+	}
+}
+
+func TestClient_ScreenshotErrorWrapsSentinel(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"code": "ERR::SCREENSHOT::INVALID_CONTENT_TYPE", "message": "invalid content type", "http_code": 422}`))
+	})
+
+	_, err := client.Screenshot(&ScreenshotConfig{URL: "https://example.com"})
+	if !errors.Is(err, ErrScreenshotAPIFailed) {
+		t.Fatalf("expected ErrScreenshotAPIFailed, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != "ERR::SCREENSHOT::INVALID_CONTENT_TYPE" {
+		t.Errorf("expected wrapped *APIError, got %v", err)
+	}
+}
+
+func TestClient_ExtractErrorWrapsSentinel(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"code": "ERR::EXTRACTION::CONTENT_TYPE_NOT_SUPPORTED", "message": "content type not supported", "http_code": 422}`))
+	})
+
+	_, err := client.Extract(&ExtractionConfig{Body: []byte("<html></html>"), ContentType: "text/html", ExtractionPrompt: "title"})
+	if !errors.Is(err, ErrExtractionAPIFailed) {
+		t.Fatalf("expected ErrExtractionAPIFailed, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("expected wrapped 422 *APIError, got %v", err)
+	}
+}
+
+func TestClient_ScrapeWithScreenshotDownloadsImages(t *testing.T) {
+	var imageCalls int32
+	var client *Client
+	client = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/screenshots/fullpage.jpg" {
+			atomic.AddInt32(&imageCalls, 1)
+			if r.URL.Query().Get("key") != "__API_KEY__" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte("JPEGDATA"))
+			return
+		}
+		if r.URL.Query().Get("screenshots[fullpage]") != "fullpage" || r.URL.Query().Get("render_js") != "true" {
+			t.Errorf("screenshot params not set: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"result": {"success": true, "status": "DONE", "status_code": 200, "content": "<html></html>",
+			"screenshots": {"fullpage": {"extension": "jpg", "format": "fullpage", "size": 8, "url": "%s/screenshots/fullpage.jpg"}}}}`, client.host)
+	})
+
+	config := &ScrapeConfig{URL: "https://example.com"}
+	result, err := client.ScrapeWithScreenshot(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.RenderJS || config.Screenshots != nil {
+		t.Errorf("ScrapeWithScreenshot modified the caller's config: %+v", config)
+	}
+	shot := result.Result.Screenshots["fullpage"]
+	image, err := shot.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(image) != "JPEGDATA" {
+		t.Errorf("image = %q", image)
+	}
+	if imageCalls != 1 {
+		t.Errorf("image fetched %d times, want 1", imageCalls)
+	}
+}
+
+func TestClient_ScrapeWithScreenshotWithContextCancelsDownloads(t *testing.T) {
+	var client *Client
+	client = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/screenshots/fullpage.jpg" {
+			<-r.Context().Done()
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"result": {"success": true, "status": "DONE", "status_code": 200, "content": "<html></html>",
+			"screenshots": {"fullpage": {"extension": "jpg", "format": "fullpage", "size": 8, "url": "%s/screenshots/fullpage.jpg"}}}}`, client.host)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	result, err := client.ScrapeWithScreenshotWithContext(ctx, &ScrapeConfig{URL: "https://example.com"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if result == nil || result.Result.Content != "<html></html>" {
+		t.Errorf("expected the scrape alongside the download error, got %+v", result)
+	}
+}
+
+func TestClient_MaxResponseBytes(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"result": {"success": true, "status": "DONE", "status_code": 200, "content": "%s"}}`, strings.Repeat("a", 4096))
+	})
+	client.SetMaxResponseBytes(1024)
+
+	_, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"})
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, got %v", err)
+	}
+
+	client.SetMaxResponseBytes(0)
+	if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+		t.Fatalf("unlimited client should accept the response: %v", err)
+	}
+}
+
+func TestClient_WithKey(t *testing.T) {
+	var keys []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.URL.Query().Get("key"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+
+	other := client.WithKey("__OTHER_KEY__")
+	if _, err := other.Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[0] != "__OTHER_KEY__" || keys[1] != "__API_KEY__" {
+		t.Errorf("keys = %v", keys)
+	}
+	if other.HTTPClient() != client.HTTPClient() {
+		t.Error("WithKey copy should share the HTTP client")
+	}
+}
+
+func TestClient_WithKeyInterceptorsAreIndependent(t *testing.T) {
+	var seen []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+	// Leave spare capacity so a shared backing array would be written in place.
+	client.requestInterceptors = make([]RequestInterceptor, 0, 4)
+	client.AddRequestInterceptor(func(*http.Request) { seen = append(seen, "original") })
+
+	other := client.WithKey("__OTHER_KEY__")
+	other.AddRequestInterceptor(func(*http.Request) { seen = append(seen, "copy") })
+	other.AddResponseInterceptor(func(*http.Response) { seen = append(seen, "copy response") })
+	client.AddRequestInterceptor(func(*http.Request) { seen = append(seen, "original second") })
+
+	if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"original", "original second"}; !slices.Equal(seen, want) {
+		t.Errorf("original ran %v, want %v", seen, want)
+	}
+	seen = nil
+	if _, err := other.Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"original", "copy", "copy response"}; !slices.Equal(seen, want) {
+		t.Errorf("copy ran %v, want %v", seen, want)
+	}
+}
+
+// Run with -race: SetAPIKey must not race with in-flight scrapes.
+func TestClient_SetAPIKeyConcurrentWithScrapes(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+
+	configs := make([]*ScrapeConfig, 20)
+	for i := range configs {
+		configs[i] = &ScrapeConfig{URL: "https://example.com"}
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			client.SetAPIKey(fmt.Sprintf("__API_KEY_%d__", i))
+		}
+	}()
+	for item := range client.ConcurrentScrape(configs, 4) {
+		if item.Error != nil {
+			t.Error(item.Error)
+		}
+	}
+	<-done
+}
+
+func TestClient_ScrapeBinaryBody(t *testing.T) {
+	payload := []byte{0x08, 0x96, 0x01, 0x00, 0xff}
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !bytes.Equal(body, payload) {
+			t.Errorf("body = %x, want %x", body, payload)
+		}
+		if got := r.URL.Query().Get("headers[content-type]"); got != "application/x-protobuf" {
+			t.Errorf("content-type = %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+
+	_, err := client.Scrape(&ScrapeConfig{
+		URL:       "https://example.com/rpc",
+		Method:    "POST",
+		BodyBytes: payload,
+		Headers:   map[string]string{"content-type": "application/x-protobuf"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2 (body must be replayed on retry)", calls)
+	}
+}
+
+func TestClient_ScrapeForwardsContentTypeFromAnyHeaderField(t *testing.T) {
+	cases := map[string]*ScrapeConfig{
+		"HeadersMulti":   {HeadersMulti: http.Header{"Content-Type": {"application/json"}}},
+		"OrderedHeaders": {OrderedHeaders: []OrderedHeader{{Name: "Content-Type", Value: "application/json"}}},
+	}
+	for name, config := range cases {
+		t.Run(name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Content-Type"); got != "application/json" {
+					t.Errorf("API request Content-Type = %q, want application/json", got)
+				}
+				if body, _ := io.ReadAll(r.Body); string(body) != `{"a":1}` {
+					t.Errorf("body = %s", body)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(scrapeDoneResponse))
+			})
+			config.URL = "https://example.com/api"
+			config.Method = "POST"
+			config.Data = map[string]interface{}{"a": 1}
+			if _, err := client.Scrape(config); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestClient_ScrapeRejectsMultipleBodies(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request expected")
+	})
+	_, err := client.Scrape(&ScrapeConfig{URL: "https://example.com", Method: "POST", Body: "a", BodyBytes: []byte("b")})
+	if !errors.Is(err, ErrScrapeConfig) {
+		t.Fatalf("expected ErrScrapeConfig, got %v", err)
+	}
+}
+
+func TestClient_SetUserAgent(t *testing.T) {
+	var userAgents []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+
+	if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	client.SetUserAgent("MyApp/1.2.3")
+	if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(userAgents) != 2 || userAgents[0] != "Scrapfly-Go-SDK" || userAgents[1] != "Scrapfly-Go-SDK MyApp/1.2.3" {
+		t.Errorf("user agents = %q", userAgents)
+	}
+}
+
+func newLargeObjectTestClient(t *testing.T, objectFetches *int32) *Client {
+	t.Helper()
+	var client *Client
+	client = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/object/1" {
+			atomic.AddInt32(objectFetches, 1)
+			if r.URL.Query().Get("key") != "__API_KEY__" {
+				t.Errorf("key missing in large object query")
+			}
+			_, _ = w.Write([]byte("<html>large</html>"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"result": {"success": true, "status": "DONE", "status_code": 200, "format": "clob", "content": "%s/object/1"}}`, client.host)
+	})
+	return client
+}
+
+func TestClient_ScrapeFetchesLargeObjectsByDefault(t *testing.T) {
+	var fetches int32
+	client := newLargeObjectTestClient(t, &fetches)
+
+	result, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Result.Content != "<html>large</html>" || result.Result.Format != "text" {
+		t.Errorf("content = %q, format = %q", result.Result.Content, result.Result.Format)
+	}
+	if result.IsLargeObject() {
+		t.Error("IsLargeObject() = true after fetch")
+	}
+	if fetches != 1 {
+		t.Errorf("object fetches = %d, want 1", fetches)
+	}
+}
+
+func TestClient_ScrapeDeferLargeObjects(t *testing.T) {
+	var fetches int32
+	client := newLargeObjectTestClient(t, &fetches)
+
+	result, err := client.Scrape(&ScrapeConfig{URL: "https://example.com", DeferLargeObjects: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsLargeObject() || !strings.HasSuffix(result.Result.Content, "/object/1") {
+		t.Fatalf("content = %q, format = %q", result.Result.Content, result.Result.Format)
+	}
+	if fetches != 0 {
+		t.Fatalf("object fetched before ResolveLargeObject")
+	}
+
+	if err := result.ResolveLargeObject(); err != nil {
+		t.Fatal(err)
+	}
+	if result.Result.Content != "<html>large</html>" || result.Result.Format != "text" {
+		t.Errorf("content = %q, format = %q", result.Result.Content, result.Result.Format)
+	}
+	if err := result.ResolveLargeObject(); err != nil {
+		t.Fatal(err)
+	}
+	if fetches != 1 {
+		t.Errorf("object fetches = %d, want 1", fetches)
+	}
+}
+
+func TestScrapeResult_ResolveLargeObjectWithoutClient(t *testing.T) {
+	r := &ScrapeResult{}
+	r.Result.Format = "clob"
+	r.Result.Content = "https://api.scrapfly.io/object/1"
+	if err := r.ResolveLargeObject(); err == nil {
+		t.Error("expected error for result not returned by a Client")
+	}
+}
+
+func TestClient_InterceptorsRunOnEveryAttempt(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Trace") != "abc" {
+			t.Errorf("interceptor header missing on attempt %d", calls+1)
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+
+	var order []string
+	var statuses []int
+	client.AddRequestInterceptor(func(req *http.Request) {
+		order = append(order, "first")
+		req.Header.Set("X-Trace", "abc")
+	})
+	client.AddRequestInterceptor(func(req *http.Request) {
+		order = append(order, "second")
+	})
+	client.AddResponseInterceptor(func(resp *http.Response) {
+		statuses = append(statuses, resp.StatusCode)
+	})
+
+	if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"first", "second", "first", "second"}; strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Errorf("request interceptor calls = %v, want %v", order, want)
+	}
+	if len(statuses) != 2 || statuses[0] != http.StatusBadGateway || statuses[1] != http.StatusOK {
+		t.Errorf("response interceptor statuses = %v", statuses)
+	}
+}
+
+func TestClient_InterceptorsSharedWithKey(t *testing.T) {
+	var intercepted int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+	client.AddRequestInterceptor(func(req *http.Request) {
+		atomic.AddInt32(&intercepted, 1)
+	})
+	client.AddRequestInterceptor(nil)
+
+	if _, err := client.WithKey("OTHER").Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if intercepted != 1 {
+		t.Errorf("intercepted = %d, want 1", intercepted)
+	}
+}
+
+func TestClient_ScrapeEscalatesProxyPool(t *testing.T) {
+	var pools []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		pools = append(pools, query.Get("proxy_pool"))
+		w.Header().Set("Content-Type", "application/json")
+		if query.Get("proxy_pool") != string(PublicResidentialPool) {
+			_, _ = w.Write([]byte(scrapeErrorResponse("ERR::ASP::SHIELD_PROTECTION_FAILED", false)))
+			return
+		}
+		if query.Get("asp") != "true" {
+			t.Error("escalated scrape must enable ASP")
+		}
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+
+	result, err := client.Scrape(&ScrapeConfig{URL: "https://example.com", ProxyPool: PublicDataCenterPool, EscalateProxyPool: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Result.Content != "ok" {
+		t.Errorf("content = %q", result.Result.Content)
+	}
+	if len(pools) != 2 || pools[0] != string(PublicDataCenterPool) || pools[1] != string(PublicResidentialPool) {
+		t.Errorf("pools = %v", pools)
+	}
+}
+
+func TestClient_ScrapeEscalatesBlockedPage(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&calls, 1) == 1 {
+			_, _ = w.Write([]byte(`{"result": {"success": true, "status": "DONE", "status_code": 200, "content": "<title>Just a moment...</title>", "content_type": "text/html"}, "context": {"asp": {"blocked": true}, "cost": {"total": 1}}}`))
+			return
+		}
+		if got := r.URL.Query().Get("cost_budget"); got != "29" {
+			t.Errorf("escalated cost_budget = %q, want 29", got)
+		}
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+
+	result, err := client.Scrape(&ScrapeConfig{URL: "https://example.com", ASP: true, CostBudget: 30, EscalateProxyPool: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(&calls); result.IsBlocked() || got != 2 {
+		t.Errorf("calls = %d, blocked = %v", got, result.IsBlocked())
+	}
+}
+
+func TestClient_ScrapeEscalatesUpstreamForbidden(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&calls, 1) == 1 {
+			_, _ = w.Write([]byte(`{"result": {"success": true, "status": "DONE", "status_code": 403, "content": "denied", "content_type": "text/html"}, "context": {"cost": {"total": 1}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+
+	result, err := client.Scrape(&ScrapeConfig{URL: "https://example.com", EscalateProxyPool: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 || result.Result.Content != "ok" {
+		t.Errorf("calls = %d, content = %q", got, result.Result.Content)
+	}
+}
+
+func TestClient_ScrapeDoesNotEscalateOnContentHeuristic(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"result": {"success": true, "status": "DONE", "status_code": 200, "content": "<form><div class=\"g-recaptcha\"></div></form>", "content_type": "text/html"}, "context": {"cost": {"total": 1}}}`))
+	})
+
+	result, err := client.Scrape(&ScrapeConfig{URL: "https://example.com", EscalateProxyPool: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 || !result.IsBlocked() {
+		t.Errorf("calls = %d, blocked = %v, want a single attempt on a heuristic match", got, result.IsBlocked())
+	}
+}
+
+func TestClient_ScrapeEscalationRespectsCostBudget(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"result": {"success": true, "status": "DONE", "status_code": 200, "content": "<title>Just a moment...</title>", "content_type": "text/html"}, "context": {"asp": {"blocked": true}, "cost": {"total": 30}}}`))
+	})
+
+	result, err := client.Scrape(&ScrapeConfig{URL: "https://example.com", ASP: true, CostBudget: 30, EscalateProxyPool: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 || !result.IsBlocked() {
+		t.Errorf("calls = %d, want a single attempt once the budget is spent", calls)
+	}
+}
+
+func TestClient_ScrapeNoEscalationByDefault(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeErrorResponse("ERR::ASP::SHIELD_PROTECTION_FAILED", false)))
+	})
+	if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"}); !errors.Is(err, ErrASPBypassFailed) {
+		t.Errorf("expected ErrASPBypassFailed, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestClient_ConcurrentExtract(t *testing.T) {
+	var inFlight, maxInFlight int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(string(body), "broken") {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"code": "ERR::EXTRACTION::CONTENT_TYPE_NOT_SUPPORTED", "message": "unsupported", "http_code": 422}`))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"data": {"title": %q}, "content_type": "application/json"}`, body)
+	})
+	client.SetGlobalConcurrency(2)
+
+	docs := []string{"<h1>a</h1>", "<h1>b</h1>", "broken", "<h1>c</h1>", "<h1>d</h1>"}
+	configs := make([]*ExtractionConfig, len(docs))
+	for i, doc := range docs {
+		configs[i] = &ExtractionConfig{Body: []byte(doc), ContentType: "text/html", ExtractionPrompt: "title"}
+	}
+
+	titles := map[string]bool{}
+	var failed int
+	for item := range client.ConcurrentExtract(configs, 4) {
+		if item.Error != nil {
+			failed++
+			if !errors.Is(item.Error, ErrExtractionAPIFailed) || string(item.Config.Body) != "broken" {
+				t.Errorf("unexpected error for %q: %v", item.Config.Body, item.Error)
+			}
+			continue
+		}
+		title := item.Result.Data.(map[string]interface{})["title"].(string)
+		if title != string(item.Config.Body) {
+			t.Errorf("result %q reported for config %q", title, item.Config.Body)
+		}
+		titles[title] = true
+	}
+	if failed != 1 || len(titles) != 4 {
+		t.Errorf("failed = %d, titles = %v", failed, titles)
+	}
+	if maxInFlight > 2 {
+		t.Errorf("max in flight = %d, want global limit 2", maxInFlight)
+	}
+}
+
+func TestClient_ScrapeProxifiedStreamsUpstreamResponse(t *testing.T) {
+	payload := strings.Repeat("x", 1<<16)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("proxified_response") != "true" {
+			t.Errorf("proxified_response not sent: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("X-Scrapfly-Api-Cost", "1")
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = io.WriteString(w, payload)
+	})
+
+	resp, err := client.ScrapeProxified(&ScrapeConfig{URL: "https://example.com/big.bin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent || resp.Header.Get("Content-Type") != "application/octet-stream" {
+		t.Errorf("status = %d, headers = %v", resp.StatusCode, resp.Header)
+	}
+	n, err := io.Copy(io.Discard, resp.Body)
+	if err != nil || n != int64(len(payload)) {
+		t.Errorf("streamed %d bytes, err = %v", n, err)
+	}
+
+	if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com", ProxifiedResponse: true}); !errors.Is(err, ErrScrapeConfig) {
+		t.Errorf("Scrape with ProxifiedResponse: expected ErrScrapeConfig, got %v", err)
+	}
+}
+
+func TestClient_ScrapeStream(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("X-Scrapfly-Api-Cost", "3")
+		w.Header().Set("X-Scrapfly-Log", "01HXYZ")
+		w.Header().Set("X-Scrapfly-Content-Format", "raw")
+		w.WriteHeader(http.StatusNotFound)
+		flusher := w.(http.Flusher)
+		for i := 0; i < 3; i++ {
+			_, _ = fmt.Fprintf(w, "line %d\n", i)
+			flusher.Flush()
+		}
+	})
+
+	body, meta, err := client.ScrapeStream(&ScrapeConfig{URL: "https://example.com/dump.txt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	if meta.StatusCode != http.StatusNotFound || meta.ContentType != "text/plain" || meta.ContentFormat != "raw" ||
+		meta.Log != "01HXYZ" || meta.Cost != 3 || meta.Header.Get("X-Scrapfly-Log") != "01HXYZ" {
+		t.Errorf("meta = %+v", meta)
+	}
+	content, err := io.ReadAll(body)
+	if err != nil || string(content) != "line 0\nline 1\nline 2\n" {
+		t.Errorf("content = %q, err = %v", content, err)
+	}
+}
+
+func TestClient_ScrapeStreamError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Scrapfly-Reject-Code", "ERR::PROXY::TIMEOUT")
+		w.Header().Set("X-Scrapfly-Reject-Description", "proxy timeout")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	})
+
+	body, meta, err := client.ScrapeStream(&ScrapeConfig{URL: "https://example.com"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != "ERR::PROXY::TIMEOUT" || body != nil || meta != nil {
+		t.Errorf("expected *APIError, got body = %v, meta = %v, err = %v", body, meta, err)
+	}
+}
+
+func TestClient_SetDefaultScrapeConfig(t *testing.T) {
+	var queries []url.Values
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+	defaults := &ScrapeConfig{
+		ASP:       true,
+		Country:   "us",
+		ProxyPool: PublicResidentialPool,
+		Headers:   map[string]string{"User-Agent": "default-agent", "X-Team": "growth"},
+		Tags:      []string{"default"},
+	}
+	client.SetDefaultScrapeConfig(defaults)
+	defaults.Country = "fr" // the template is copied
+
+	config := &ScrapeConfig{
+		URL:     "https://example.com",
+		Country: "de",
+		Headers: map[string]string{"user-agent": "my-agent"},
+	}
+	if _, err := client.Scrape(config); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com", Tags: []string{"mine"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	first := queries[0]
+	for param, want := range map[string]string{
+		"asp":                 "true",
+		"country":             "de",
+		"proxy_pool":          string(PublicResidentialPool),
+		"headers[user-agent]": "my-agent",
+		"headers[x-team]":     "growth",
+		"tags":                "default",
+	} {
+		if got := first.Get(param); got != want {
+			t.Errorf("%s = %q, want %q", param, got, want)
+		}
+	}
+	if second := queries[1]; second.Get("country") != "us" || second.Get("tags") != "mine" {
+		t.Errorf("second scrape: country = %q, tags = %q", second.Get("country"), second.Get("tags"))
+	}
+	if config.ASP || len(config.Headers) != 1 {
+		t.Errorf("per-call config was modified: %+v", config)
+	}
+
+	client.SetDefaultScrapeConfig(nil)
+	if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if queries[2].Get("asp") != "" {
+		t.Errorf("defaults still applied after reset: %v", queries[2])
+	}
+}
+
+func TestClient_ScrapeWithContextCancelAbortsInFlight(t *testing.T) {
+	release := make(chan struct{})
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+	_, err := client.ScrapeWithContext(ctx, &ScrapeConfig{URL: "https://example.com"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("scrape returned after %s, want right after cancel", elapsed)
+	}
+}
+
+func TestClient_SetForceHTTP1(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Proto", r.Proto)
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	proto := func(client *Client) string {
+		t.Helper()
+		var got string
+		client.AddResponseInterceptor(func(resp *http.Response) { got = resp.Proto })
+		if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+	newClient := func() *Client {
+		client, err := NewWithHost("__API_KEY__", server.URL, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.SetHTTPClient(server.Client())
+		return client
+	}
+
+	client := newClient()
+	if got := proto(client); got != "HTTP/2.0" {
+		t.Fatalf("baseline proto = %s, want HTTP/2.0", got)
+	}
+	shared := server.Client().Transport.(*http.Transport)
+
+	client = newClient()
+	if err := client.SetForceHTTP1(true); err != nil {
+		t.Fatal(err)
+	}
+	transport := client.httpClient.Transport.(*http.Transport)
+	if transport == shared || transport.ForceAttemptHTTP2 || transport.TLSNextProto == nil {
+		t.Errorf("transport not pinned to HTTP/1.1: ForceAttemptHTTP2 = %v, TLSNextProto = %v", transport.ForceAttemptHTTP2, transport.TLSNextProto)
+	}
+	if got := proto(client); got != "HTTP/1.1" {
+		t.Errorf("proto = %s, want HTTP/1.1", got)
+	}
+
+	if err := client.SetForceHTTP1(false); err != nil {
+		t.Fatal(err)
+	}
+	if transport := client.httpClient.Transport.(*http.Transport); !transport.ForceAttemptHTTP2 || transport.TLSNextProto != nil {
+		t.Errorf("HTTP/2 not restored")
+	}
+
+	custom := newClient()
+	custom.SetHTTPClient(&http.Client{Transport: roundTripperFunc(http.DefaultTransport.RoundTrip)})
+	if err := custom.SetForceHTTP1(true); err == nil {
+		t.Error("expected an error for a custom transport")
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestClient_SetPathPrefix(t *testing.T) {
+	var paths []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+	client.SetPathPrefix("v1/")
+	metrics := &recordingMetrics{}
+	client.SetMetrics(metrics)
+
+	if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.WithKey("__OTHER_KEY__").Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	client.SetPathPrefix("")
+	if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(paths, []string{"/v1/scrape", "/v1/scrape", "/scrape"}) {
+		t.Errorf("paths = %v", paths)
+	}
+	if len(metrics.requests) != 3 {
+		t.Errorf("observed %d requests, want 3", len(metrics.requests))
+	}
+	for _, request := range metrics.requests {
+		if request.endpoint != "/scrape" {
+			t.Errorf("metrics endpoint = %q, want /scrape", request.endpoint)
+		}
+	}
+	if u, _ := url.Parse("https://api.example.com/v1"); metricsEndpoint(u, "/v1") != "/" {
+		t.Errorf("metricsEndpoint of the bare prefix = %q, want /", metricsEndpoint(u, "/v1"))
+	}
+	if u, _ := url.Parse("https://api.example.com/v10/scrape"); metricsEndpoint(u, "/v1") != "/v10" {
+		t.Errorf("metricsEndpoint only strips whole path segments")
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsRetryableError(t *testing.T) {
+	for name, tc := range map[string]struct {
+		err  error
+		want bool
+	}{
+		"timeout":            {&url.Error{Op: "Get", URL: "https://api.scrapfly.io", Err: timeoutError{}}, true},
+		"client timeout":     {fmt.Errorf("%w (Client.Timeout exceeded while awaiting headers)", context.DeadlineExceeded), true},
+		"connection reset":   {&net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, true},
+		"broken pipe":        {&net.OpError{Op: "write", Net: "tcp", Err: os.NewSyscallError("write", syscall.EPIPE)}, true},
+		"connection refused": {&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, false},
+		"other dial error":   {&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ENETUNREACH)}, false},
+		"idle connection":    {&url.Error{Op: "Get", URL: "https://api.scrapfly.io", Err: io.EOF}, true},
+		"dns temporary":      {&net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "server misbehaving", Name: "api.scrapfly.io", IsTemporary: true}}, true},
+		"dns not found":      {&net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "api.scrapfly.io", IsNotFound: true}}, false},
+		"canceled":           {&url.Error{Op: "Get", URL: "https://api.scrapfly.io", Err: context.Canceled}, false},
+		"unknown authority":  {&url.Error{Op: "Get", URL: "https://api.scrapfly.io", Err: &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}}, false},
+		"hostname mismatch":  {x509.HostnameError{Host: "api.scrapfly.io", Certificate: &x509.Certificate{}}, false},
+		"unsupported scheme": {&url.Error{Op: "Get", URL: "ftp://api.scrapfly.io", Err: errors.New(`unsupported protocol scheme "ftp"`)}, false},
+	} {
+		if got := isRetryableError(tc.err); got != tc.want {
+			t.Errorf("%s: isRetryableError(%v) = %v, want %v", name, tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestFetchWithRetry_ErrorClassification(t *testing.T) {
+	for name, tc := range map[string]struct {
+		err   error
+		calls int32
+	}{
+		"connection reset":   {os.NewSyscallError("read", syscall.ECONNRESET), 3},
+		"connection refused": {&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, 1},
+		"unresolvable host":  {&net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "api.scrapfly.invalid", IsNotFound: true}}, 1},
+		"canceled":           {context.Canceled, 1},
+		"tls verification":   {&tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}, 1},
+	} {
+		var calls int32
+		client, _ := New("__API_KEY__")
+		client.SetHTTPClient(&http.Client{Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			atomic.AddInt32(&calls, 1)
+			return nil, tc.err
+		})})
+		req, _ := http.NewRequest(http.MethodGet, "https://api.scrapfly.io/account", nil)
+		if _, err := fetchWithRetry(client, req, 3, time.Millisecond); !errors.Is(err, tc.err) {
+			t.Errorf("%s: expected %v, got %v", name, tc.err, err)
+		}
+		if got := atomic.LoadInt32(&calls); got != tc.calls {
+			t.Errorf("%s: calls = %d, want %d", name, got, tc.calls)
+		}
+	}
+}
+
+func TestFetchWithRetry_RefusedConnectionIsNotRetried(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	var dials int32
+	dialer := &net.Dialer{}
+	client, _ := New("__API_KEY__")
+	client.SetHTTPClient(&http.Client{Transport: &http.Transport{DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+		atomic.AddInt32(&dials, 1)
+		return dialer.DialContext(ctx, network, address)
+	}}})
+	req, _ := http.NewRequest(http.MethodGet, "http://"+addr+"/account", nil)
+	if _, err := fetchWithRetry(client, req, 3, time.Millisecond); !errors.Is(err, syscall.ECONNREFUSED) {
+		t.Fatalf("expected ECONNREFUSED, got %v", err)
+	}
+	if got := atomic.LoadInt32(&dials); got != 1 {
+		t.Errorf("dials = %d, want 1", got)
+	}
+}
+
+func TestFetchWithRetry_ContextDeadline(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-r.Context().Done()
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, client.endpoint("/account"), nil)
+	if _, err := fetchWithRetry(client, req, 3, time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestClient_SetMaxTotalDuration(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	client.SetMaxTotalDuration(100 * time.Millisecond)
+
+	start := time.Now()
+	_, err := client.WithKey("__OTHER_KEY__").Scrape(&ScrapeConfig{URL: "https://example.com"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	// Without the limit, the 3 attempts and their 1s backoff take 2s.
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("scrape took %v, want about 100ms", elapsed)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestClient_ScrapeProxifiedLeavesConfigReusable(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("proxified_response") == "true" {
+			_, _ = io.WriteString(w, "<html></html>")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+
+	config := &ScrapeConfig{URL: "https://example.com"}
+	body, _, err := client.ScrapeStream(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body.Close()
+	if config.ProxifiedResponse {
+		t.Error("ScrapeStream set ProxifiedResponse on the caller's config")
+	}
+	if _, err := client.Scrape(config); err != nil {
+		t.Errorf("Scrape with a config reused from ScrapeStream: %v", err)
+	}
+}
+
+func TestClient_ScrapeWithScreenshotReturnsFailedResult(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeErrorResponse("ERR::ASP::SHIELD_PROTECTION_FAILED", false)))
+	})
+	result, err := client.ScrapeWithScreenshot(&ScrapeConfig{URL: "https://example.com"})
+	if !errors.Is(err, ErrASPBypassFailed) {
+		t.Fatalf("expected ErrASPBypassFailed, got %v", err)
+	}
+	if result == nil {
+		t.Error("expected the failed result alongside the error")
+	}
+}
@@ -111,7 +111,7 @@ func ProjectSalt(apiKey string) string {
 
 // CloudBrowserProjectSalt returns the project salt for this client's api key.
 func (c *Client) CloudBrowserProjectSalt() string {
-	return ProjectSalt(c.key)
+	return ProjectSalt(c.APIKey())
 }
 
 // CloudBrowser returns the Cloud Browser WebSocket connection URL.
@@ -126,7 +126,7 @@ func (c *Client) CloudBrowser(config *CloudBrowserConfig) string {
 	}
 
 	params := url.Values{}
-	params.Set("api_key", c.key)
+	params.Set("api_key", c.APIKey())
 
 	if config != nil {
 		if config.ProxyPool != "" {
@@ -282,7 +282,7 @@ func (c *Client) cloudBrowserRESTHost() string {
 // to a browser with cookies/state pre-loaded.
 func (c *Client) CloudBrowserUnblock(config UnblockConfig) (*UnblockResult, error) {
 	host := c.cloudBrowserRESTHost()
-	reqURL := fmt.Sprintf("%s/unblock?key=%s", host, url.QueryEscape(c.key))
+	reqURL := fmt.Sprintf("%s/unblock?key=%s", host, url.QueryEscape(c.APIKey()))
 
 	body, err := json.Marshal(config)
 	if err != nil {
@@ -294,9 +294,9 @@ func (c *Client) CloudBrowserUnblock(config UnblockConfig) (*UnblockResult, erro
 		return nil, fmt.Errorf("failed to create unblock request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("unblock request failed: %w", err)
 	}
@@ -322,15 +322,15 @@ func (c *Client) CloudBrowserUnblock(config UnblockConfig) (*UnblockResult, erro
 // CloudBrowserSessionStop terminates a browser session.
 func (c *Client) CloudBrowserSessionStop(sessionID string) error {
 	host := c.cloudBrowserRESTHost()
-	reqURL := fmt.Sprintf("%s/session/%s/stop?key=%s", host, url.PathEscape(sessionID), url.QueryEscape(c.key))
+	reqURL := fmt.Sprintf("%s/session/%s/stop?key=%s", host, url.PathEscape(sessionID), url.QueryEscape(c.APIKey()))
 
 	req, err := http.NewRequest(http.MethodPost, reqURL, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create stop request: %w", err)
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("stop request failed: %w", err)
 	}
@@ -348,15 +348,15 @@ func (c *Client) CloudBrowserSessionStop(sessionID string) error {
 // `unavailable`, `disabled`), `metadata`, `video_url`, and `retry_after_ms`.
 func (c *Client) CloudBrowserPlayback(runID string) (map[string]interface{}, error) {
 	host := c.cloudBrowserRESTHost()
-	reqURL := fmt.Sprintf("%s/run/%s/playback?key=%s", host, url.PathEscape(runID), url.QueryEscape(c.key))
+	reqURL := fmt.Sprintf("%s/run/%s/playback?key=%s", host, url.PathEscape(runID), url.QueryEscape(c.APIKey()))
 
 	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create playback request: %w", err)
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("playback request failed: %w", err)
 	}
@@ -420,15 +420,15 @@ func (c *Client) CloudBrowserWaitForPlayback(runID string, timeout, fallbackInte
 // Returns the raw video bytes (webm format).
 func (c *Client) CloudBrowserVideo(runID string) ([]byte, error) {
 	host := c.cloudBrowserRESTHost()
-	reqURL := fmt.Sprintf("%s/run/%s/video?key=%s", host, url.PathEscape(runID), url.QueryEscape(c.key))
+	reqURL := fmt.Sprintf("%s/run/%s/video?key=%s", host, url.PathEscape(runID), url.QueryEscape(c.APIKey()))
 
 	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create video request: %w", err)
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("video request failed: %w", err)
 	}
@@ -445,15 +445,15 @@ func (c *Client) CloudBrowserVideo(runID string) ([]byte, error) {
 // CloudBrowserSessions lists all running Cloud Browser sessions.
 func (c *Client) CloudBrowserSessions() (map[string]interface{}, error) {
 	host := c.cloudBrowserRESTHost()
-	reqURL := fmt.Sprintf("%s/sessions?key=%s", host, url.QueryEscape(c.key))
+	reqURL := fmt.Sprintf("%s/sessions?key=%s", host, url.QueryEscape(c.APIKey()))
 
 	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create sessions request: %w", err)
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("sessions request failed: %w", err)
 	}
@@ -474,15 +474,15 @@ func (c *Client) CloudBrowserSessions() (map[string]interface{}, error) {
 // CloudBrowserExtensionList lists all browser extensions for the account.
 func (c *Client) CloudBrowserExtensionList() (map[string]interface{}, error) {
 	host := c.cloudBrowserRESTHost()
-	reqURL := fmt.Sprintf("%s/extension?key=%s", host, url.QueryEscape(c.key))
+	reqURL := fmt.Sprintf("%s/extension?key=%s", host, url.QueryEscape(c.APIKey()))
 
 	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create extension list request: %w", err)
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("extension list request failed: %w", err)
 	}
@@ -503,15 +503,15 @@ func (c *Client) CloudBrowserExtensionList() (map[string]interface{}, error) {
 // CloudBrowserExtensionGet returns details of a specific extension.
 func (c *Client) CloudBrowserExtensionGet(extensionID string) (map[string]interface{}, error) {
 	host := c.cloudBrowserRESTHost()
-	reqURL := fmt.Sprintf("%s/extension/%s?key=%s", host, url.PathEscape(extensionID), url.QueryEscape(c.key))
+	reqURL := fmt.Sprintf("%s/extension/%s?key=%s", host, url.PathEscape(extensionID), url.QueryEscape(c.APIKey()))
 
 	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create extension get request: %w", err)
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("extension get request failed: %w", err)
 	}
@@ -532,7 +532,7 @@ func (c *Client) CloudBrowserExtensionGet(extensionID string) (map[string]interf
 // CloudBrowserExtensionUpload uploads a browser extension from a local .zip or .crx file.
 func (c *Client) CloudBrowserExtensionUpload(filePath string) (map[string]interface{}, error) {
 	host := c.cloudBrowserRESTHost()
-	reqURL := fmt.Sprintf("%s/extension?key=%s", host, url.QueryEscape(c.key))
+	reqURL := fmt.Sprintf("%s/extension?key=%s", host, url.QueryEscape(c.APIKey()))
 
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -556,9 +556,9 @@ func (c *Client) CloudBrowserExtensionUpload(filePath string) (map[string]interf
 		return nil, fmt.Errorf("failed to create upload request: %w", err)
 	}
 	req.Header.Set("Content-Type", writer.FormDataContentType())
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("upload request failed: %w", err)
 	}
@@ -579,15 +579,15 @@ func (c *Client) CloudBrowserExtensionUpload(filePath string) (map[string]interf
 // CloudBrowserExtensionDelete deletes a browser extension by ID.
 func (c *Client) CloudBrowserExtensionDelete(extensionID string) (map[string]interface{}, error) {
 	host := c.cloudBrowserRESTHost()
-	reqURL := fmt.Sprintf("%s/extension/%s?key=%s", host, url.PathEscape(extensionID), url.QueryEscape(c.key))
+	reqURL := fmt.Sprintf("%s/extension/%s?key=%s", host, url.PathEscape(extensionID), url.QueryEscape(c.APIKey()))
 
 	req, err := http.NewRequest(http.MethodDelete, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create extension delete request: %w", err)
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("extension delete request failed: %w", err)
 	}
@@ -644,7 +644,7 @@ func vaultErrorf(op string, status int, body []byte) error {
 // or for vault rotation.
 func (c *Client) CloudBrowserVaultCreate(name, description string) (map[string]interface{}, error) {
 	host := c.cloudBrowserRESTHost()
-	reqURL := fmt.Sprintf("%s/vault?key=%s", host, url.QueryEscape(c.key))
+	reqURL := fmt.Sprintf("%s/vault?key=%s", host, url.QueryEscape(c.APIKey()))
 
 	body, err := json.Marshal(map[string]string{"name": name, "description": description})
 	if err != nil {
@@ -656,9 +656,9 @@ func (c *Client) CloudBrowserVaultCreate(name, description string) (map[string]i
 		return nil, fmt.Errorf("failed to create vault create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("vault create request failed: %w", err)
 	}
@@ -680,15 +680,15 @@ func (c *Client) CloudBrowserVaultCreate(name, description string) (map[string]i
 // the current project + environment. Response shape: {vaults: [...]}.
 func (c *Client) CloudBrowserVaultList() (map[string]interface{}, error) {
 	host := c.cloudBrowserRESTHost()
-	reqURL := fmt.Sprintf("%s/vault?key=%s", host, url.QueryEscape(c.key))
+	reqURL := fmt.Sprintf("%s/vault?key=%s", host, url.QueryEscape(c.APIKey()))
 
 	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create vault list request: %w", err)
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("vault list request failed: %w", err)
 	}
@@ -710,15 +710,15 @@ func (c *Client) CloudBrowserVaultList() (map[string]interface{}, error) {
 // No secret material is included in the response.
 func (c *Client) CloudBrowserVaultGet(vaultID string) (map[string]interface{}, error) {
 	host := c.cloudBrowserRESTHost()
-	reqURL := fmt.Sprintf("%s/vault/%s?key=%s", host, url.PathEscape(vaultID), url.QueryEscape(c.key))
+	reqURL := fmt.Sprintf("%s/vault/%s?key=%s", host, url.PathEscape(vaultID), url.QueryEscape(c.APIKey()))
 
 	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create vault get request: %w", err)
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("vault get request failed: %w", err)
 	}
@@ -741,7 +741,7 @@ func (c *Client) CloudBrowserVaultGet(vaultID string) (map[string]interface{}, e
 // only the fields you want to overwrite.
 func (c *Client) CloudBrowserVaultUpdate(vaultID string, name, description string) (map[string]interface{}, error) {
 	host := c.cloudBrowserRESTHost()
-	reqURL := fmt.Sprintf("%s/vault/%s?key=%s", host, url.PathEscape(vaultID), url.QueryEscape(c.key))
+	reqURL := fmt.Sprintf("%s/vault/%s?key=%s", host, url.PathEscape(vaultID), url.QueryEscape(c.APIKey()))
 
 	patch := map[string]string{}
 	if name != "" {
@@ -760,9 +760,9 @@ func (c *Client) CloudBrowserVaultUpdate(vaultID string, name, description strin
 		return nil, fmt.Errorf("failed to create vault update request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("vault update request failed: %w", err)
 	}
@@ -784,15 +784,15 @@ func (c *Client) CloudBrowserVaultUpdate(vaultID string, name, description strin
 // Cannot be reversed.
 func (c *Client) CloudBrowserVaultDelete(vaultID string) (map[string]interface{}, error) {
 	host := c.cloudBrowserRESTHost()
-	reqURL := fmt.Sprintf("%s/vault/%s?key=%s", host, url.PathEscape(vaultID), url.QueryEscape(c.key))
+	reqURL := fmt.Sprintf("%s/vault/%s?key=%s", host, url.PathEscape(vaultID), url.QueryEscape(c.APIKey()))
 
 	req, err := http.NewRequest(http.MethodDelete, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create vault delete request: %w", err)
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("vault delete request failed: %w", err)
 	}
@@ -821,16 +821,16 @@ func (c *Client) CloudBrowserVaultDelete(vaultID string) (map[string]interface{}
 // breadcrumbs — see the security contract at the top of this section.
 func (c *Client) CloudBrowserVaultRotate(vaultID, currentVaultKey string) (map[string]interface{}, error) {
 	host := c.cloudBrowserRESTHost()
-	reqURL := fmt.Sprintf("%s/vault/%s/rotate?key=%s", host, url.PathEscape(vaultID), url.QueryEscape(c.key))
+	reqURL := fmt.Sprintf("%s/vault/%s/rotate?key=%s", host, url.PathEscape(vaultID), url.QueryEscape(c.APIKey()))
 
 	req, err := http.NewRequest(http.MethodPost, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create vault rotate request: %w", err)
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 	req.Header.Set("X-Vault-Key", currentVaultKey)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("vault rotate request failed: %w", err)
 	}
@@ -856,15 +856,15 @@ func (c *Client) CloudBrowserVaultRotate(vaultID, currentVaultKey string) (map[s
 // blob locally with the customer-held vault key.
 func (c *Client) CloudBrowserVaultItemList(vaultID string) (map[string]interface{}, error) {
 	host := c.cloudBrowserRESTHost()
-	reqURL := fmt.Sprintf("%s/vault/%s/item?key=%s", host, url.PathEscape(vaultID), url.QueryEscape(c.key))
+	reqURL := fmt.Sprintf("%s/vault/%s/item?key=%s", host, url.PathEscape(vaultID), url.QueryEscape(c.APIKey()))
 
 	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create vault item list request: %w", err)
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("vault item list request failed: %w", err)
 	}
@@ -897,7 +897,7 @@ func (c *Client) CloudBrowserVaultItemList(vaultID string) (map[string]interface
 // material — the SDK will not log it.
 func (c *Client) CloudBrowserVaultItemCreate(vaultID, vaultKey string, item map[string]interface{}) (map[string]interface{}, error) {
 	host := c.cloudBrowserRESTHost()
-	reqURL := fmt.Sprintf("%s/vault/%s/item?key=%s", host, url.PathEscape(vaultID), url.QueryEscape(c.key))
+	reqURL := fmt.Sprintf("%s/vault/%s/item?key=%s", host, url.PathEscape(vaultID), url.QueryEscape(c.APIKey()))
 
 	body, err := json.Marshal(item)
 	if err != nil {
@@ -909,10 +909,10 @@ func (c *Client) CloudBrowserVaultItemCreate(vaultID, vaultKey string, item map[
 		return nil, fmt.Errorf("failed to create vault item create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 	req.Header.Set("X-Vault-Key", vaultKey)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("vault item create request failed: %w", err)
 	}
@@ -939,7 +939,7 @@ func (c *Client) CloudBrowserVaultItemCreate(vaultID, vaultKey string, item map[
 func (c *Client) CloudBrowserVaultItemUpdate(vaultID, itemID, vaultKey string, patch map[string]interface{}) (map[string]interface{}, error) {
 	host := c.cloudBrowserRESTHost()
 	reqURL := fmt.Sprintf("%s/vault/%s/item/%s?key=%s",
-		host, url.PathEscape(vaultID), url.PathEscape(itemID), url.QueryEscape(c.key))
+		host, url.PathEscape(vaultID), url.PathEscape(itemID), url.QueryEscape(c.APIKey()))
 
 	body, err := json.Marshal(patch)
 	if err != nil {
@@ -951,12 +951,12 @@ func (c *Client) CloudBrowserVaultItemUpdate(vaultID, itemID, vaultKey string, p
 		return nil, fmt.Errorf("failed to create vault item update request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 	if vaultKey != "" {
 		req.Header.Set("X-Vault-Key", vaultKey)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("vault item update request failed: %w", err)
 	}
@@ -979,15 +979,15 @@ func (c *Client) CloudBrowserVaultItemUpdate(vaultID, itemID, vaultKey string, p
 func (c *Client) CloudBrowserVaultItemDelete(vaultID, itemID string) (map[string]interface{}, error) {
 	host := c.cloudBrowserRESTHost()
 	reqURL := fmt.Sprintf("%s/vault/%s/item/%s?key=%s",
-		host, url.PathEscape(vaultID), url.PathEscape(itemID), url.QueryEscape(c.key))
+		host, url.PathEscape(vaultID), url.PathEscape(itemID), url.QueryEscape(c.APIKey()))
 
 	req, err := http.NewRequest(http.MethodDelete, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create vault item delete request: %w", err)
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("vault item delete request failed: %w", err)
 	}
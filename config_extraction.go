@@ -1,7 +1,9 @@
 package scrapfly
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 )
@@ -19,6 +21,151 @@ const (
 	DEFLATE CompressionFormat = "deflate"
 )
 
+// ExtractionFormatter post-processes the value of an extraction template
+// selector. Formatters go in the "formatters" list of a selector, in
+// ExtractionConfig.ExtractionEphemeralTemplate or
+// ScrapeConfig.ExtractionEphemeralTemplate, and are encoded as
+// {"name": ..., "args": {...}}.
+//
+// The Formatter* constants name the formatters the SDK knows, each with a
+// constructor building and a Validate check of its arguments. Others can
+// still be set with a literal ExtractionFormatter, see
+// https://scrapfly.io/docs/extraction-api/rules-and-template for the list.
+//
+// Example:
+//
+//	template := map[string]interface{}{
+//	    "source": "html",
+//	    "selectors": []map[string]interface{}{{
+//	        "name":       "date_posted",
+//	        "type":       "css",
+//	        "query":      "[data-testid='review-date']::text",
+//	        "formatters": []scrapfly.ExtractionFormatter{scrapfly.DatetimeFormatter("%Y, %b %d — %A")},
+//	    }},
+//	}
+type ExtractionFormatter struct {
+	// Name is the formatter name, e.g. FormatterDatetime.
+	Name string `json:"name"`
+	// Args are the formatter arguments.
+	Args map[string]interface{} `json:"args,omitempty"`
+}
+
+// Extraction template formatter names with a typed constructor.
+const (
+	// FormatterDatetime parses a date and renders it with a strftime
+	// format, see DatetimeFormatter.
+	FormatterDatetime = "datetime"
+	// FormatterLowercase lowercases the value, see LowercaseFormatter.
+	FormatterLowercase = "lowercase"
+	// FormatterUppercase uppercases the value, see UppercaseFormatter.
+	FormatterUppercase = "uppercase"
+	// FormatterTrim strips the surrounding whitespace of the value, see
+	// TrimFormatter.
+	FormatterTrim = "trim"
+	// FormatterRegex keeps the part of the value matching a regular
+	// expression, see RegexFormatter.
+	FormatterRegex = "regex"
+)
+
+// DatetimeFormatter returns a datetime formatter rendering dates with the
+// strftime format, e.g. "%Y-%m-%d".
+func DatetimeFormatter(format string) ExtractionFormatter {
+	return ExtractionFormatter{Name: FormatterDatetime, Args: map[string]interface{}{"format": format}}
+}
+
+// LowercaseFormatter returns a formatter lowercasing the value.
+func LowercaseFormatter() ExtractionFormatter {
+	return ExtractionFormatter{Name: FormatterLowercase}
+}
+
+// UppercaseFormatter returns a formatter uppercasing the value.
+func UppercaseFormatter() ExtractionFormatter {
+	return ExtractionFormatter{Name: FormatterUppercase}
+}
+
+// TrimFormatter returns a formatter stripping the surrounding whitespace of
+// the value.
+func TrimFormatter() ExtractionFormatter {
+	return ExtractionFormatter{Name: FormatterTrim}
+}
+
+// RegexFormatter returns a formatter keeping the part of the value matching
+// pattern. The pattern is evaluated by the API, not by Go's regexp package,
+// so only its presence is checked.
+func RegexFormatter(pattern string) ExtractionFormatter {
+	return ExtractionFormatter{Name: FormatterRegex, Args: map[string]interface{}{"pattern": pattern}}
+}
+
+// Validate checks the formatter has a name and, for the Formatter*
+// formatters, the arguments they expect, returning an ErrExtractionConfig
+// wrapped error otherwise.
+func (f ExtractionFormatter) Validate() error {
+	if err := f.validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrExtractionConfig, err)
+	}
+	return nil
+}
+
+// validate is Validate without the error sentinel, so callers can wrap it
+// with the one of the config being validated.
+func (f ExtractionFormatter) validate() error {
+	switch f.Name {
+	case "":
+		return errors.New("formatter name is required")
+	case FormatterDatetime:
+		return f.requireStringArg("format")
+	case FormatterRegex:
+		return f.requireStringArg("pattern")
+	case FormatterLowercase, FormatterUppercase, FormatterTrim:
+		if len(f.Args) > 0 {
+			return fmt.Errorf("%s formatter takes no arguments, got %v", f.Name, f.Args)
+		}
+	}
+	return nil
+}
+
+func (f ExtractionFormatter) requireStringArg(name string) error {
+	if value, ok := f.Args[name].(string); !ok || value == "" {
+		return fmt.Errorf("%s formatter requires a non-empty %q string argument", f.Name, name)
+	}
+	return nil
+}
+
+// validateTemplateFormatters validates the ExtractionFormatter values found
+// anywhere in an extraction template. The error carries no sentinel, callers
+// wrap it with the one of their config.
+func validateTemplateFormatters(value interface{}) error {
+	switch v := value.(type) {
+	case ExtractionFormatter:
+		return v.validate()
+	case []ExtractionFormatter:
+		for _, formatter := range v {
+			if err := formatter.validate(); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			if err := validateTemplateFormatters(item); err != nil {
+				return err
+			}
+		}
+	case []map[string]interface{}:
+		for _, item := range v {
+			if err := validateTemplateFormatters(item); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := validateTemplateFormatters(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // ExtractionConfig configures an AI-powered data extraction request to the Scrapfly API.
 //
 // This struct contains all available options for extracting structured data from
@@ -66,6 +213,21 @@ type ExtractionConfig struct {
 	Timeout int
 }
 
+// Clone returns a deep copy of the config, Body and the ephemeral template
+// included, so variants (another prompt, another model) can be derived
+// without sharing state with c.
+//
+// Example:
+//
+//	summary := config.Clone()
+//	summary.ExtractionPrompt = "summarize the article"
+func (c *ExtractionConfig) Clone() *ExtractionConfig {
+	clone := *c
+	clone.Body = bytes.Clone(c.Body)
+	clone.ExtractionEphemeralTemplate = cloneJSONMap(c.ExtractionEphemeralTemplate)
+	return &clone
+}
+
 // toAPIParams converts the ExtractionConfig into URL parameters for the Scrapfly API.
 // This is an internal method used by the Client to prepare API requests.
 func (c *ExtractionConfig) toAPIParams() (url.Values, error) {
@@ -108,6 +270,9 @@ func (c *ExtractionConfig) toAPIParams() (url.Values, error) {
 		params.Set("extraction_template", "persistent:"+c.ExtractionTemplate)
 	}
 	if c.ExtractionEphemeralTemplate != nil {
+		if err := validateTemplateFormatters(c.ExtractionEphemeralTemplate); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrExtractionConfig, err)
+		}
 		templateJSON, err := json.Marshal(c.ExtractionEphemeralTemplate)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal extraction_ephemeral_template: %w", err)
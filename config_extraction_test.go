@@ -0,0 +1,91 @@
+package scrapfly
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExtractionConfig_Clone(t *testing.T) {
+	cfg := &ExtractionConfig{
+		Body:                        []byte("<html></html>"),
+		ContentType:                 "text/html",
+		ExtractionEphemeralTemplate: map[string]interface{}{"source": "html", "selectors": []interface{}{"h1"}},
+	}
+	variant := cfg.Clone()
+	variant.Body[1] = 'X'
+	variant.ExtractionEphemeralTemplate["source"] = "json"
+	variant.ExtractionEphemeralTemplate["selectors"].([]interface{})[0] = "h2"
+
+	if string(cfg.Body) != "<html></html>" {
+		t.Errorf("body shared, got %q", cfg.Body)
+	}
+	if cfg.ExtractionEphemeralTemplate["source"] != "html" || cfg.ExtractionEphemeralTemplate["selectors"].([]interface{})[0] != "h1" {
+		t.Errorf("template shared, got %v", cfg.ExtractionEphemeralTemplate)
+	}
+
+	prompt := (&ExtractionConfig{Body: []byte("x"), ContentType: "text/plain", ExtractionPrompt: "title"}).Clone()
+	if prompt.ExtractionPrompt != "title" || prompt.ExtractionEphemeralTemplate != nil {
+		t.Errorf("clone = %+v", prompt)
+	}
+}
+
+func TestExtractionFormatter(t *testing.T) {
+	template := map[string]interface{}{
+		"source": "html",
+		"selectors": []map[string]interface{}{{
+			"name":       "date_posted",
+			"type":       "css",
+			"query":      "[data-testid='review-date']::text",
+			"formatters": []ExtractionFormatter{DatetimeFormatter("%Y, %b %d — %A")},
+		}},
+	}
+	cfg := &ExtractionConfig{Body: []byte("<html></html>"), ContentType: "text/html", ExtractionEphemeralTemplate: template}
+	params, err := cfg.toAPIParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, _ := strings.CutPrefix(params.Get("extraction_template"), "ephemeral:")
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"formatters":[{"name":"datetime","args":{"format":"%Y, %b %d — %A"}}]`; !strings.Contains(string(decoded), want) {
+		t.Errorf("template %s does not contain %s", decoded, want)
+	}
+
+	for _, formatter := range []ExtractionFormatter{
+		{},
+		DatetimeFormatter(""),
+		{Name: FormatterDatetime, Args: map[string]interface{}{"format": 1}},
+		RegexFormatter(""),
+		{Name: FormatterLowercase, Args: map[string]interface{}{"locale": "tr"}},
+	} {
+		if err := formatter.Validate(); !errors.Is(err, ErrExtractionConfig) {
+			t.Errorf("%+v: Validate() = %v, expected ErrExtractionConfig", formatter, err)
+		}
+		template["selectors"].([]map[string]interface{})[0]["formatters"] = []interface{}{formatter}
+		if _, err := cfg.toAPIParams(); !errors.Is(err, ErrExtractionConfig) {
+			t.Errorf("%+v: expected ErrExtractionConfig, got %v", formatter, err)
+		}
+		scrape := &ScrapeConfig{URL: "https://example.com", ExtractionEphemeralTemplate: template}
+		if err := scrape.Validate(); !errors.Is(err, ErrScrapeConfig) || errors.Is(err, ErrExtractionConfig) {
+			t.Errorf("%+v: expected ErrScrapeConfig only, got %v", formatter, err)
+		}
+	}
+
+	for _, formatter := range []ExtractionFormatter{LowercaseFormatter(), UppercaseFormatter(), TrimFormatter(), RegexFormatter(`\d+(\.\d+)?`)} {
+		if err := formatter.Validate(); err != nil {
+			t.Errorf("%+v: %v", formatter, err)
+		}
+	}
+	encodedRegex, _ := json.Marshal(RegexFormatter(`\d+`))
+	if string(encodedRegex) != `{"name":"regex","args":{"pattern":"\\d+"}}` {
+		t.Errorf("regex formatter encodes as %s", encodedRegex)
+	}
+	if encodedTrim, _ := json.Marshal(TrimFormatter()); string(encodedTrim) != `{"name":"trim"}` {
+		t.Errorf("trim formatter encodes as %s", encodedTrim)
+	}
+}
@@ -1,10 +1,16 @@
 package scrapfly
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
+	"net/http"
 	"net/url"
+	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -29,22 +35,41 @@ import (
 type ScrapeConfig struct {
 	// URL is the target URL to scrape (required).
 	URL string `required:"true"`
-	// Method is the HTTP method to use (GET, POST, PUT, PATCH). Defaults to GET.
+	// Method is the HTTP method to use (GET, POST, PUT, PATCH, OPTIONS, HEAD). Defaults to GET.
+	// DELETE, TRACE and CONNECT are not supported by the API, see HttpMethod.
 	Method HttpMethod
 	// Body is the raw request body for POST/PUT/PATCH requests.
 	Body string
+	// BodyBytes is a binary request body (protobuf, images...), sent as is.
+	// Cannot be used together with Body or Data.
+	BodyBytes []byte
 	// Data is a map that will be encoded as request body based on Content-Type.
 	// Cannot be used together with Body.
 	Data map[string]interface{}
 	// Headers are custom HTTP headers to send with the request.
+	// An Accept-Encoding header only applies to the upstream request: the
+	// API response itself is always decompressed by the client transport.
 	Headers map[string]string
+	// HeadersMulti are custom HTTP headers that may carry several values
+	// (e.g. Accept, X-Forwarded-For). They are sent in addition to Headers.
+	HeadersMulti http.Header
+	// OrderedHeaders are custom HTTP headers sent in the given order, for
+	// targets fingerprinting header order. They are emitted after every
+	// other parameter, so Headers and HeadersMulti come first; a name set
+	// here replaces the same header from Headers and HeadersMulti. Cookies
+	// are appended to the first Cookie entry, if any.
+	OrderedHeaders []OrderedHeader
 	// Cookies are cookies to include in the request.
 	Cookies map[string]string
 	// Country specifies the proxy country code (e.g., "us", "uk", "de").
 	// Supports ISO 3166-1 alpha-2 country codes.
 	Country string
+	// CountryFallback lists alternate proxy countries Scrapfly may use, in
+	// order, when no proxy is available in Country. They are sent after
+	// Country in the comma separated country parameter.
+	CountryFallback []string
 	// ProxyPool specifies which proxy pool to use.
-	ProxyPool ProxyPool
+	ProxyPool ProxyPool `validate:"enum"`
 	// RenderJS enables JavaScript rendering using a headless browser.
 	RenderJS bool
 	// ASP enables Anti-Scraping Protection bypass.
@@ -82,6 +107,12 @@ type ScrapeConfig struct {
 	FormatOptions []FormatOption `validate:"enum"`
 	// ExtractionTemplate is the name of a saved extraction template.
 	// it is exclusve with other extraction options
+	//
+	// Inline extraction runs on the scraped page, so the options of the
+	// standalone Extract API describing the document (ContentType, Charset,
+	// URL) are taken from the upstream response and the requested URL, and
+	// Webhook and Timeout are those of the scrape; the Scrape API has no
+	// separate extraction parameters for them.
 	ExtractionTemplate string `exclusive:"extraction"`
 	// ExtractionEphemeralTemplate is an inline extraction template definition.
 	// it is exclusve with other extraction options
@@ -98,6 +129,13 @@ type ScrapeConfig struct {
 	RenderingWait int
 	// AutoScroll automatically scrolls the page to load lazy content (requires RenderJS).
 	AutoScroll bool
+	// AutoScrollCount scrolls to the bottom of the page this many more times
+	// after the automatic scroll, for infinite scroll feeds (requires
+	// AutoScroll). The scrolls are sent as JS scenario steps, after JSScenario.
+	AutoScrollCount int
+	// AutoScrollDelay is the wait in milliseconds after each of the
+	// AutoScrollCount scrolls, giving the page time to load the next items.
+	AutoScrollDelay int
 	// Screenshots is a map of screenshot names to CSS selectors (requires RenderJS).
 	Screenshots map[string]string
 	// ScreenshotFlags are options for screenshot capture.
@@ -106,9 +144,15 @@ type ScrapeConfig struct {
 	JS string
 	// JSScenario is a sequence of browser actions to perform (requires RenderJS).
 	JSScenario []js_scenario.JSScenarioStep
-	// OS spoofs the operating system in the User-Agent.
+	// OS spoofs the operating system in the User-Agent. It must be one of
+	// the OperatingSystem values unless AllowCustomOS is set.
 	OS string
-	// Lang sets the Accept-Language header values.
+	// AllowCustomOS sends OS as is, without checking it against the
+	// OperatingSystem values, for values newer than this SDK.
+	AllowCustomOS bool
+	// Lang sets the Accept-Language header values, as language tags
+	// such as "en", "en-US" or "zh-Hant-TW", optionally q-weighted
+	// ("fr;q=0.9") or comma separated ("fr-CH, fr;q=0.9").
 	Lang []string
 	// BrowserBrand selects the Chromium-based browser for fingerprint generation.
 	// Valid values: "chrome", "edge", "brave", "opera". Empty = default chrome.
@@ -116,23 +160,223 @@ type ScrapeConfig struct {
 	BrowserBrand string
 	// CostBudget limits the maximum API credit cost for ASP retries.
 	// ASP dynamically upgrades proxy/browser to bypass protection; this caps spending.
+	// The request is aborted if it would exceed the budget. Zero means no budget.
 	CostBudget int
 	// Geolocation spoofs the browser's geolocation. Format: "latitude,longitude".
 	Geolocation string
 	// RenderingStage controls when the browser considers the page loaded (requires RenderJS).
-	// Valid values: "complete" (default), "domcontentloaded".
+	// Valid values: "complete" (default), "domcontentloaded", see the
+	// RenderingStage constants. It can be combined with RenderingWait,
+	// which waits on top of the stage.
 	RenderingStage string
 	// ProxifiedResponse returns the raw upstream response (target's status,
 	// headers, body) instead of the JSON envelope. When true, callers must
 	// use ScrapeProxified() instead of Scrape(), which returns *http.Response.
 	ProxifiedResponse bool
+	// DeferLargeObjects leaves large object content (clob/blob formats)
+	// unfetched: Content keeps the object URL until
+	// ScrapeResult.ResolveLargeObject is called. This is a client-side option.
+	DeferLargeObjects bool
+	// EscalateProxyPool retries a blocked scrape once with
+	// PublicResidentialPool and ASP enabled. A scrape counts as blocked when
+	// it fails with ErrASPBypassFailed, ErrProxyFailed or an upstream 403/429,
+	// or succeeds with an upstream 403/429 or an ASP context reporting the
+	// target blocked it. Pages merely matching BlockSignatures do not
+	// escalate, check ScrapeResult.IsBlocked to act on those. When CostBudget
+	// is set, the retry only gets what the first attempt left of it.
+	// This is a client-side option.
+	EscalateProxyPool bool
+	// CompressUpstream asks the target website for a compressed transfer
+	// (Accept-Encoding: gzip, deflate, br) unless Headers or HeadersMulti
+	// already set Accept-Encoding. It lowers Context.BandwidthConsumed, see
+	// ScrapeResult.BandwidthSaved. Scrapfly decompresses the page before
+	// returning it, so Result.Content is never compressed.
+	CompressUpstream bool
+}
+
+// upstreamAcceptEncoding is the Accept-Encoding sent with CompressUpstream.
+const upstreamAcceptEncoding = "gzip, deflate, br"
+
+// OrderedHeader is one entry of ScrapeConfig.OrderedHeaders. A name may
+// appear several times to send a multi-value header.
+type OrderedHeader struct {
+	Name  string
+	Value string
+}
+
+// headerView merges Headers, HeadersMulti and OrderedHeaders the way they are
+// sent upstream: a name set in OrderedHeaders replaces the same header from
+// the other two.
+func (c *ScrapeConfig) headerView() http.Header {
+	headers := http.Header{}
+	for key, value := range c.Headers {
+		headers.Add(key, value)
+	}
+	for key, values := range c.HeadersMulti {
+		for _, value := range values {
+			headers.Add(key, value)
+		}
+	}
+	ordered := http.Header{}
+	for _, header := range c.OrderedHeaders {
+		ordered.Add(header.Name, header.Value)
+	}
+	for key, values := range ordered {
+		headers[key] = values
+	}
+	return headers
+}
+
+// contentType returns the content-type header set in Headers, HeadersMulti
+// or OrderedHeaders.
+func (c *ScrapeConfig) contentType() (string, bool) {
+	if values := c.headerView().Values("Content-Type"); len(values) > 0 {
+		return values[0], true
+	}
+	return "", false
+}
+
+// hasExtraction reports whether inline extraction is configured.
+func (c *ScrapeConfig) hasExtraction() bool {
+	return c.ExtractionTemplate != "" || c.ExtractionEphemeralTemplate != nil || c.ExtractionPrompt != "" || c.ExtractionModel != ""
+}
+
+// hasHeader reports whether name is set in Headers, HeadersMulti or
+// OrderedHeaders.
+func (c *ScrapeConfig) hasHeader(name string) bool {
+	return len(c.headerView().Values(name)) > 0
+}
+
+// encodeAPIParams encodes params as a query string, moving the
+// OrderedHeaders to the end, in order. url.Values.Encode sorts keys, which
+// would otherwise shuffle them.
+func (c *ScrapeConfig) encodeAPIParams(params url.Values) string {
+	if len(c.OrderedHeaders) == 0 {
+		return params.Encode()
+	}
+	orderedHeaders := c.orderedHeaders()
+	params = maps.Clone(params)
+	for _, header := range orderedHeaders {
+		params.Del(orderedHeaderParam(header.Name))
+	}
+	var b strings.Builder
+	b.WriteString(params.Encode())
+	for _, header := range orderedHeaders {
+		if b.Len() > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(url.QueryEscape(orderedHeaderParam(header.Name)))
+		b.WriteByte('=')
+		b.WriteString(url.QueryEscape(header.Value))
+	}
+	return b.String()
+}
+
+// orderedHeaders returns OrderedHeaders with Cookies appended to the first
+// Cookie entry, the way they are sent.
+func (c *ScrapeConfig) orderedHeaders() []OrderedHeader {
+	i := c.orderedCookieIndex()
+	if len(c.Cookies) == 0 || i < 0 {
+		return c.OrderedHeaders
+	}
+	headers := slices.Clone(c.OrderedHeaders)
+	headers[i].Value += "; " + c.cookieHeader()
+	return headers
+}
+
+// orderedCookieIndex returns the index of the first Cookie entry of
+// OrderedHeaders, or -1.
+func (c *ScrapeConfig) orderedCookieIndex() int {
+	return slices.IndexFunc(c.OrderedHeaders, func(header OrderedHeader) bool {
+		return strings.EqualFold(header.Name, "Cookie")
+	})
+}
+
+// cookieHeader returns Cookies as a Cookie header value.
+func (c *ScrapeConfig) cookieHeader() string {
+	var cookieParts []string
+	for name, value := range c.Cookies {
+		cookieParts = append(cookieParts, fmt.Sprintf("%s=%s", name, value))
+	}
+	return strings.Join(cookieParts, "; ")
+}
+
+func orderedHeaderParam(name string) string {
+	return fmt.Sprintf("headers[%s]", strings.ToLower(name))
+}
+
+// requestBody returns a fresh reader over the request body, BodyBytes taking
+// precedence over Body. It is called again for every retry.
+func (c *ScrapeConfig) requestBody() io.Reader {
+	if c.BodyBytes != nil {
+		return bytes.NewReader(c.BodyBytes)
+	}
+	return strings.NewReader(c.Body)
+}
+
+// validateHttpMethod checks method against the methods supported by the
+// scrape endpoint, see HttpMethod. The method is case-insensitive.
+func validateHttpMethod(method HttpMethod) error {
+	if method == "" || HttpMethod(strings.ToUpper(string(method))).IsValid() {
+		return nil
+	}
+	switch strings.ToUpper(string(method)) {
+	case http.MethodDelete, http.MethodTrace, http.MethodConnect:
+		return fmt.Errorf("%w: HTTP method %s is not supported by the Scrape API, supported methods are %v", ErrScrapeConfig, strings.ToUpper(string(method)), HttpMethod("").Enum())
+	}
+	return fmt.Errorf("%w: invalid HTTP method %q, supported methods are %v", ErrScrapeConfig, method, HttpMethod("").Enum())
+}
+
+// escalated returns a copy of the config for an EscalateProxyPool retry, with
+// the budget left after spending cost. ok is false when nothing is left.
+func (c *ScrapeConfig) escalated(cost int) (config *ScrapeConfig, ok bool) {
+	escalated := *c
+	escalated.ProxyPool = PublicResidentialPool
+	escalated.ASP = true
+	escalated.EscalateProxyPool = false
+	if c.CostBudget > 0 {
+		escalated.CostBudget = c.CostBudget - cost
+		if escalated.CostBudget <= 0 {
+			return nil, false
+		}
+	}
+	return &escalated, true
+}
+
+// scenarioSteps returns JSScenario followed by the AutoScrollCount scrolls,
+// each followed by an AutoScrollDelay wait.
+func (c *ScrapeConfig) scenarioSteps() []js_scenario.JSScenarioStep {
+	if c.AutoScrollCount == 0 {
+		return c.JSScenario
+	}
+	steps := slices.Clone(c.JSScenario)
+	for i := 0; i < c.AutoScrollCount; i++ {
+		steps = append(steps, js_scenario.JSScenarioStep{"scroll": map[string]any{"selector": "bottom"}})
+		if c.AutoScrollDelay > 0 {
+			steps = append(steps, js_scenario.JSScenarioStep{"wait": c.AutoScrollDelay})
+		}
+	}
+	return steps
+}
+
+// countries returns Country followed by CountryFallback, lowercased and
+// without duplicates.
+func (c *ScrapeConfig) countries() []string {
+	var countries []string
+	for _, country := range append([]string{c.Country}, c.CountryFallback...) {
+		country = strings.ToLower(country)
+		if country != "" && !slices.Contains(countries, country) {
+			countries = append(countries, country)
+		}
+	}
+	return countries
 }
 
 // processBody handles the Data and Body fields for POST/PUT/PATCH requests.
 // It converts the Data map to the appropriate body format based on Content-Type.
 // This is an internal method used during request preparation.
 func (c *ScrapeConfig) processBody() error {
-	method := strings.ToUpper(c.Method.String())
+	method := strings.ToUpper(string(c.Method))
 	if method != "POST" && method != "PUT" && method != "PATCH" {
 		return nil
 	}
@@ -140,9 +384,22 @@ func (c *ScrapeConfig) processBody() error {
 	if c.Body != "" && c.Data != nil {
 		return fmt.Errorf("%w: cannot set both Body and Data", ErrScrapeConfig)
 	}
+	if c.BodyBytes != nil && (c.Body != "" || c.Data != nil) {
+		return fmt.Errorf("%w: cannot set BodyBytes together with Body or Data", ErrScrapeConfig)
+	}
+
+	if c.BodyBytes != nil {
+		if _, ok := c.contentType(); !ok {
+			if c.Headers == nil {
+				c.Headers = make(map[string]string)
+			}
+			c.Headers["content-type"] = "application/octet-stream"
+		}
+		return nil
+	}
 
 	if c.Data != nil {
-		contentType, ok := c.Headers["content-type"]
+		contentType, ok := c.contentType()
 		if !ok {
 			contentType = "application/x-www-form-urlencoded"
 			if c.Headers == nil {
@@ -170,7 +427,7 @@ func (c *ScrapeConfig) processBody() error {
 	}
 
 	if c.Body != "" {
-		if _, ok := c.Headers["content-type"]; !ok {
+		if _, ok := c.contentType(); !ok {
 			if c.Headers == nil {
 				c.Headers = make(map[string]string)
 			}
@@ -180,20 +437,219 @@ func (c *ScrapeConfig) processBody() error {
 	return nil
 }
 
+// SetGraphQL configures c to send a GraphQL query: Method is set to POST,
+// the content-type header to application/json and Body to the
+// {"query": ..., "variables": ...} document. Variables may be nil. Any Data
+// or BodyBytes previously set is cleared.
+//
+// Example:
+//
+//	config := &scrapfly.ScrapeConfig{URL: "https://web-scraping.dev/api/graphql"}
+//	err := config.SetGraphQL(`query Reviews($first: Int) { reviews(first: $first) { edges { node { text } } } }`,
+//	    map[string]interface{}{"first": 10})
+func (c *ScrapeConfig) SetGraphQL(query string, variables map[string]interface{}) error {
+	body, err := json.Marshal(struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables,omitempty"`
+	}{query, variables})
+	if err != nil {
+		return fmt.Errorf("%w: failed to encode GraphQL variables: %w", ErrScrapeConfig, err)
+	}
+	c.Method = HttpMethodPost
+	c.Body = string(body)
+	c.Data = nil
+	c.BodyBytes = nil
+	for key := range c.Headers {
+		if strings.EqualFold(key, "content-type") {
+			delete(c.Headers, key)
+		}
+	}
+	if c.Headers == nil {
+		c.Headers = make(map[string]string)
+	}
+	c.Headers["content-type"] = "application/json"
+	return nil
+}
+
 var countryRegex = regexp.MustCompile("^([a-zA-Z]{2}|)$")
 
+// langRegex loosely matches BCP 47 language tags: a 2-3 letter language
+// followed by optional script, region or variant subtags.
+var langRegex = regexp.MustCompile("^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$")
+
+// langWeightRegex matches the q-value of an Accept-Language entry.
+var langWeightRegex = regexp.MustCompile(`^q=(0(\.[0-9]{0,3})?|1(\.0{0,3})?)$`)
+
+// validLang reports whether lang is a language tag, or an Accept-Language
+// style list of them with optional weights ("fr-CH, fr;q=0.9").
+func validLang(lang string) bool {
+	for _, entry := range strings.Split(lang, ",") {
+		tag, weight, weighted := strings.Cut(entry, ";")
+		if !langRegex.MatchString(strings.TrimSpace(tag)) {
+			return false
+		}
+		if weighted && !langWeightRegex.MatchString(strings.TrimSpace(weight)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone returns a deep copy of the config: slices, maps and the JS scenario
+// are copied, so the copy can be modified, or scraped concurrently, without
+// affecting c.
+//
+// Example:
+//
+//	linux := config.Clone()
+//	linux.OS = string(scrapfly.OSLinux)
+//	linux.Tags = append(linux.Tags, "linux")
+func (c *ScrapeConfig) Clone() *ScrapeConfig {
+	clone := *c
+	clone.BodyBytes = bytes.Clone(c.BodyBytes)
+	clone.Data = cloneJSONMap(c.Data)
+	clone.Headers = maps.Clone(c.Headers)
+	clone.HeadersMulti = c.HeadersMulti.Clone()
+	clone.OrderedHeaders = slices.Clone(c.OrderedHeaders)
+	clone.Cookies = maps.Clone(c.Cookies)
+	clone.CountryFallback = slices.Clone(c.CountryFallback)
+	if c.SessionStickyProxy != nil {
+		sticky := *c.SessionStickyProxy
+		clone.SessionStickyProxy = &sticky
+	}
+	clone.Tags = slices.Clone(c.Tags)
+	clone.FormatOptions = slices.Clone(c.FormatOptions)
+	clone.ExtractionEphemeralTemplate = cloneJSONMap(c.ExtractionEphemeralTemplate)
+	clone.Screenshots = maps.Clone(c.Screenshots)
+	clone.ScreenshotFlags = slices.Clone(c.ScreenshotFlags)
+	if c.JSScenario != nil {
+		clone.JSScenario = make([]js_scenario.JSScenarioStep, len(c.JSScenario))
+		for i, step := range c.JSScenario {
+			clone.JSScenario[i] = cloneJSONMap(step)
+		}
+	}
+	clone.Lang = slices.Clone(c.Lang)
+	return &clone
+}
+
+// withDefaults returns a copy of c with its zero fields taken from
+// defaults, see Client.SetDefaultScrapeConfig. It returns c itself when
+// defaults is nil.
+func (c *ScrapeConfig) withDefaults(defaults *ScrapeConfig) *ScrapeConfig {
+	if defaults == nil {
+		return c
+	}
+	merged := c.Clone()
+	template := defaults.Clone()
+	mergedValue := reflect.ValueOf(merged).Elem()
+	templateValue := reflect.ValueOf(template).Elem()
+	for i := 0; i < mergedValue.NumField(); i++ {
+		field, fallback := mergedValue.Field(i), templateValue.Field(i)
+		if !field.CanSet() || fallback.IsZero() {
+			continue
+		}
+		switch {
+		case field.IsZero():
+			field.Set(fallback)
+		case field.Kind() == reflect.Map:
+			foldKeys := mergedValue.Type().Field(i).Name == "Headers"
+			iter := fallback.MapRange()
+			for iter.Next() {
+				if !mapHasKey(field, iter.Key(), foldKeys) {
+					field.SetMapIndex(iter.Key(), iter.Value())
+				}
+			}
+		}
+	}
+	return merged
+}
+
+// mapHasKey reports whether m has key, comparing string keys
+// case-insensitively when fold is set.
+func mapHasKey(m, key reflect.Value, fold bool) bool {
+	if !fold {
+		return m.MapIndex(key).IsValid()
+	}
+	iter := m.MapRange()
+	for iter.Next() {
+		if strings.EqualFold(iter.Key().String(), key.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the configuration without sending it, returning an
+// ErrScrapeConfig wrapped error describing the first problem found.
+// Valid configs that are likely to be blocked, such as ASP over the
+// datacenter pool or a ResidentialTargets host without the residential
+// pool, are reported as warnings through DefaultLogger.
+//
+// Example:
+//
+//	if err := config.Validate(); err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *ScrapeConfig) Validate() error {
+	if err := c.validateConfig(); err != nil {
+		return err
+	}
+	c.warnProxyPool()
+	return nil
+}
+
+// ResidentialTargets lists hosts known to block datacenter proxies. Validate
+// logs a warning when one of them, or one of its subdomains, is scraped
+// without PublicResidentialPool. Replace or extend it to match your own
+// targets, or set it to nil to disable the check.
+var ResidentialTargets = []string{
+	"amazon.com",
+	"instagram.com",
+	"linkedin.com",
+	"zillow.com",
+}
+
+// warnProxyPool logs proxy pool choices that are known to waste credits:
+// ASP over an explicitly requested datacenter pool, and ResidentialTargets
+// scraped without the residential pool. These don't make the config
+// invalid, so they are only reported through DefaultLogger.
+func (c *ScrapeConfig) warnProxyPool() {
+	if c.ProxyPool == PublicResidentialPool {
+		return
+	}
+	if c.ASP && c.ProxyPool == PublicDataCenterPool {
+		DefaultLogger.Warn("ASP is enabled with", PublicDataCenterPool, "- protected targets usually require", PublicResidentialPool)
+	}
+	parsed, err := url.Parse(c.URL)
+	if err != nil {
+		return
+	}
+	host := strings.ToLower(parsed.Hostname())
+	for _, target := range ResidentialTargets {
+		if host == target || strings.HasSuffix(host, "."+target) {
+			DefaultLogger.Warn(host, "usually blocks datacenter proxies, consider ProxyPool", PublicResidentialPool, "with ASP")
+			return
+		}
+	}
+}
+
 func (c *ScrapeConfig) validateConfig() error {
 
 	// validate exclusive fields, see struct tags
 	if err := ValidateExclusiveFields(c); err != nil {
-		return err
+		return fmt.Errorf("%w: %w", ErrScrapeConfig, err)
 	}
 	// validate required fields, see struct tags
 	if err := ValidateRequiredFields(c); err != nil {
-		return err
+		return fmt.Errorf("%w: %w", ErrScrapeConfig, err)
 	}
 	// validate enums, see struct tags
 	if err := ValidateEnums(c); err != nil {
+		return fmt.Errorf("%w: %w", ErrScrapeConfig, err)
+	}
+
+	// Method is not an enum tag: it is matched case-insensitively.
+	if err := validateHttpMethod(c.Method); err != nil {
 		return err
 	}
 
@@ -205,6 +661,38 @@ func (c *ScrapeConfig) validateConfig() error {
 			return fmt.Errorf("%w: invalid country code (ISO 3166-1 alpha-2): %s", ErrScrapeConfig, country)
 		}
 	}
+	for _, country := range c.CountryFallback {
+		if country == "" || !countryRegex.MatchString(country) {
+			return fmt.Errorf("%w: invalid fallback country code (ISO 3166-1 alpha-2): %q", ErrScrapeConfig, country)
+		}
+	}
+
+	if c.OS != "" && !c.AllowCustomOS && !OperatingSystem(strings.ToLower(c.OS)).IsValid() {
+		return fmt.Errorf("%w: invalid os %q, expected one of %v (or set AllowCustomOS)", ErrScrapeConfig, c.OS, OperatingSystem("").Enum())
+	}
+	for _, lang := range c.Lang {
+		if !validLang(lang) {
+			return fmt.Errorf("%w: invalid lang %q, expected a language tag such as en or en-US, or a weighted list such as \"fr-CH, fr;q=0.9\"", ErrScrapeConfig, lang)
+		}
+	}
+
+	if c.CostBudget < 0 {
+		return fmt.Errorf("%w: cost budget must be positive, got %d", ErrScrapeConfig, c.CostBudget)
+	}
+
+	if c.RenderingStage != "" && !RenderingStage(c.RenderingStage).IsValid() {
+		return fmt.Errorf("%w: invalid rendering stage %q, expected one of %v", ErrScrapeConfig, c.RenderingStage, RenderingStage("").Enum())
+	}
+
+	if c.AutoScrollCount < 0 || c.AutoScrollDelay < 0 {
+		return fmt.Errorf("%w: AutoScrollCount and AutoScrollDelay must be >= 0", ErrScrapeConfig)
+	}
+	if (c.AutoScrollCount > 0 || c.AutoScrollDelay > 0) && (!c.RenderJS || !c.AutoScroll) {
+		return fmt.Errorf("%w: AutoScrollCount and AutoScrollDelay require RenderJS and AutoScroll", ErrScrapeConfig)
+	}
+	if c.AutoScrollDelay > 0 && c.AutoScrollCount == 0 {
+		return fmt.Errorf("%w: AutoScrollDelay requires AutoScrollCount", ErrScrapeConfig)
+	}
 
 	if c.RenderJS {
 
@@ -223,11 +711,17 @@ func (c *ScrapeConfig) validateConfig() error {
 
 	}
 
+	if c.hasExtraction() && strings.EqualFold(string(c.Method), http.MethodHead) {
+		return fmt.Errorf("%w: extraction requires a response body, it cannot be used with HEAD requests", ErrScrapeConfig)
+	}
 	if c.ExtractionEphemeralTemplate != nil {
 		_, err := json.Marshal(c.ExtractionEphemeralTemplate)
 		if err != nil {
 			return fmt.Errorf("failed to marshal extraction_ephemeral_template: %w", err)
 		}
+		if err := validateTemplateFormatters(c.ExtractionEphemeralTemplate); err != nil {
+			return fmt.Errorf("%w: %w", ErrScrapeConfig, err)
+		}
 	}
 
 	for key, value := range c.Headers {
@@ -235,6 +729,18 @@ func (c *ScrapeConfig) validateConfig() error {
 			return fmt.Errorf("%w: headers key and value cannot be empty, found key: %s, value: %s", ErrScrapeConfig, key, value)
 		}
 	}
+	for key, values := range c.HeadersMulti {
+		for _, value := range values {
+			if key == "" || value == "" {
+				return fmt.Errorf("%w: headers key and value cannot be empty, found key: %s, value: %s", ErrScrapeConfig, key, value)
+			}
+		}
+	}
+	for _, header := range c.OrderedHeaders {
+		if header.Name == "" || header.Value == "" {
+			return fmt.Errorf("%w: headers key and value cannot be empty, found key: %s, value: %s", ErrScrapeConfig, header.Name, header.Value)
+		}
+	}
 
 	if len(c.Cookies) > 0 {
 		for name, value := range c.Cookies {
@@ -260,9 +766,8 @@ func (c *ScrapeConfig) toAPIParamsWithValidation() (url.Values, error) {
 
 	params.Set("url", c.URL)
 
-	if c.Country != "" {
-		country := strings.ToLower(c.Country)
-		params.Set("country", country)
+	if countries := c.countries(); len(countries) > 0 {
+		params.Set("country", strings.Join(countries, ","))
 	}
 
 	if c.ProxyPool != "" {
@@ -283,8 +788,8 @@ func (c *ScrapeConfig) toAPIParamsWithValidation() (url.Values, error) {
 		if c.JS != "" {
 			params.Set("js", urlSafeB64Encode(c.JS))
 		}
-		if len(c.JSScenario) > 0 {
-			scenarioJSON, _ := json.Marshal(c.JSScenario)
+		if steps := c.scenarioSteps(); len(steps) > 0 {
+			scenarioJSON, _ := json.Marshal(steps)
 			params.Set("js_scenario", urlSafeB64Encode(string(scenarioJSON)))
 		}
 		if len(c.Screenshots) > 0 {
@@ -394,13 +899,25 @@ func (c *ScrapeConfig) toAPIParamsWithValidation() (url.Values, error) {
 	for key, value := range c.Headers {
 		params.Set(fmt.Sprintf("headers[%s]", strings.ToLower(key)), value)
 	}
-
-	if len(c.Cookies) > 0 {
-		var cookieParts []string
-		for name, value := range c.Cookies {
-			cookieParts = append(cookieParts, fmt.Sprintf("%s=%s", name, value))
+	for key, values := range c.HeadersMulti {
+		for _, value := range values {
+			params.Add(fmt.Sprintf("headers[%s]", strings.ToLower(key)), value)
 		}
-		cookieHeader := strings.Join(cookieParts, "; ")
+	}
+	orderedHeaders := c.orderedHeaders()
+	for _, header := range orderedHeaders {
+		params.Del(orderedHeaderParam(header.Name))
+	}
+	for _, header := range orderedHeaders {
+		params.Add(orderedHeaderParam(header.Name), header.Value)
+	}
+	if c.CompressUpstream && !c.hasHeader("Accept-Encoding") {
+		params.Set("headers[accept-encoding]", upstreamAcceptEncoding)
+	}
+
+	// Cookies already went into an ordered Cookie header, if any.
+	if len(c.Cookies) > 0 && c.orderedCookieIndex() < 0 {
+		cookieHeader := c.cookieHeader()
 
 		existingCookie := ""
 		for k, v := range c.Headers {
@@ -408,6 +925,12 @@ func (c *ScrapeConfig) toAPIParamsWithValidation() (url.Values, error) {
 				existingCookie = v
 			}
 		}
+		if multi := c.HeadersMulti.Values("Cookie"); len(multi) > 0 {
+			if existingCookie != "" {
+				multi = append([]string{existingCookie}, multi...)
+			}
+			existingCookie = strings.Join(multi, "; ")
+		}
 		if existingCookie != "" {
 			params.Set("headers[cookie]", existingCookie+"; "+cookieHeader)
 		} else {
@@ -0,0 +1,18 @@
+package scrapfly
+
+import (
+	"testing"
+)
+
+func TestProxyContext_Helpers(t *testing.T) {
+	proxy := ProxyContext{Pool: "public_datacenter_pool"}
+	if !proxy.IsDatacenter() || proxy.IsResidential() {
+		t.Errorf("datacenter pool misdetected: %+v", proxy)
+	}
+	if proxy.ProxyPool() != PublicDataCenterPool {
+		t.Errorf("ProxyPool() = %q", proxy.ProxyPool())
+	}
+	if (ProxyContext{Pool: "custom"}).ProxyPool() != "" {
+		t.Error("unknown pool should map to empty ProxyPool")
+	}
+}
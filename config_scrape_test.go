@@ -0,0 +1,492 @@
+package scrapfly
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScrapeConfig_CostBudget(t *testing.T) {
+	cfg := &ScrapeConfig{URL: "https://example.com", ASP: true, CostBudget: 30}
+	params, err := cfg.toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := params.Get("cost_budget"); got != "30" {
+		t.Errorf("cost_budget = %q, want 30", got)
+	}
+
+	cfg = &ScrapeConfig{URL: "https://example.com"}
+	params, err = cfg.toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Has("cost_budget") {
+		t.Error("cost_budget must not be sent when unset")
+	}
+
+	cfg = &ScrapeConfig{URL: "https://example.com", CostBudget: -1}
+	if _, err := cfg.toAPIParamsWithValidation(); !errors.Is(err, ErrScrapeConfig) {
+		t.Errorf("expected ErrScrapeConfig for negative budget, got %v", err)
+	}
+}
+
+func TestScrapeConfig_ValidateOS(t *testing.T) {
+	valid := &ScrapeConfig{URL: "https://example.com", OS: string(OSMacOS)}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("macos should be valid: %v", err)
+	}
+	invalid := &ScrapeConfig{URL: "https://example.com", OS: "windoze"}
+	if err := invalid.Validate(); !errors.Is(err, ErrScrapeConfig) {
+		t.Errorf("expected ErrScrapeConfig, got %v", err)
+	}
+	custom := &ScrapeConfig{URL: "https://example.com", OS: "win11", AllowCustomOS: true}
+	params, err := custom.toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatalf("AllowCustomOS should pass through: %v", err)
+	}
+	if params.Get("os") != "win11" {
+		t.Errorf("os = %q", params.Get("os"))
+	}
+}
+
+func TestScrapeConfig_ValidateLang(t *testing.T) {
+	valid := &ScrapeConfig{URL: "https://example.com", Lang: []string{"en", "en-US", "zh-Hant-TW", "fil", "en;q=0.9", "fr-CH, fr;q=0.9", "de; q=1.0"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("expected valid langs: %v", err)
+	}
+	for _, lang := range []string{"", "english", "en_US", "e", "en-", "en;q=2", "en;x=1", "fr-CH,", "en;q=0.9;q=0.8"} {
+		cfg := &ScrapeConfig{URL: "https://example.com", Lang: []string{lang}}
+		if err := cfg.Validate(); !errors.Is(err, ErrScrapeConfig) {
+			t.Errorf("lang %q: expected ErrScrapeConfig, got %v", lang, err)
+		}
+	}
+}
+
+func TestScrapeConfig_HeadersMulti(t *testing.T) {
+	cfg := &ScrapeConfig{
+		URL:     "https://example.com",
+		Headers: map[string]string{"X-Single": "one"},
+		HeadersMulti: http.Header{
+			"X-Forwarded-For": {"10.0.0.1", "10.0.0.2"},
+			"Cookie":          {"a=1"},
+		},
+		Cookies: map[string]string{"b": "2"},
+	}
+	params, err := cfg.toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := params["headers[x-forwarded-for]"]; len(got) != 2 || got[0] != "10.0.0.1" || got[1] != "10.0.0.2" {
+		t.Errorf("headers[x-forwarded-for] = %v", got)
+	}
+	if got := params.Get("headers[x-single]"); got != "one" {
+		t.Errorf("headers[x-single] = %q", got)
+	}
+	if got := params["headers[cookie]"]; len(got) != 1 || got[0] != "a=1; b=2" {
+		t.Errorf("headers[cookie] = %v, want merged cookie", got)
+	}
+}
+
+func TestScrapeConfig_HeadersMultiContentType(t *testing.T) {
+	cfg := &ScrapeConfig{
+		URL:          "https://example.com",
+		Method:       "POST",
+		Data:         map[string]interface{}{"q": "go"},
+		HeadersMulti: http.Header{"Content-Type": {"application/json"}},
+	}
+	if err := cfg.processBody(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Body != `{"q":"go"}` {
+		t.Errorf("body = %q, want JSON encoded data", cfg.Body)
+	}
+}
+
+func TestScrapeConfig_ValidateMethod(t *testing.T) {
+	for _, method := range []HttpMethod{"", HttpMethodGet, HttpMethodPut, HttpMethodHead, "patch"} {
+		cfg := &ScrapeConfig{URL: "https://example.com", Method: method}
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("method %q rejected: %v", method, err)
+		}
+	}
+	for _, method := range []HttpMethod{http.MethodDelete, "trace", "FETCH"} {
+		cfg := &ScrapeConfig{URL: "https://example.com", Method: method}
+		if err := cfg.Validate(); !errors.Is(err, ErrScrapeConfig) {
+			t.Errorf("method %q: expected ErrScrapeConfig, got %v", method, err)
+		}
+	}
+	err := (&ScrapeConfig{URL: "https://example.com", Method: http.MethodDelete}).Validate()
+	if err == nil || !strings.Contains(err.Error(), "not supported by the Scrape API") {
+		t.Errorf("DELETE error = %v", err)
+	}
+}
+
+func TestScrapeConfig_ValidateWarnsProxyPool(t *testing.T) {
+	var out bytes.Buffer
+	captureDefaultLogger(t).SetSlog(slog.New(slog.NewTextHandler(&out, nil)))
+
+	cases := []struct {
+		cfg  ScrapeConfig
+		warn string
+	}{
+		{ScrapeConfig{URL: "https://example.com", ASP: true, ProxyPool: PublicDataCenterPool}, "ASP is enabled"},
+		{ScrapeConfig{URL: "https://www.linkedin.com/jobs"}, "www.linkedin.com usually blocks"},
+		{ScrapeConfig{URL: "https://www.linkedin.com/jobs", ASP: true, ProxyPool: PublicResidentialPool}, ""},
+		{ScrapeConfig{URL: "https://example.com", ASP: true}, ""},
+		{ScrapeConfig{URL: "https://notlinkedin.com"}, ""},
+	}
+	for _, tc := range cases {
+		out.Reset()
+		if err := tc.cfg.Validate(); err != nil {
+			t.Fatalf("%s: %v", tc.cfg.URL, err)
+		}
+		if tc.warn == "" && out.Len() > 0 {
+			t.Errorf("%s: unexpected warning %q", tc.cfg.URL, out.String())
+		}
+		if tc.warn != "" && !strings.Contains(out.String(), tc.warn) {
+			t.Errorf("%s: warning %q does not contain %q", tc.cfg.URL, out.String(), tc.warn)
+		}
+	}
+}
+
+func TestClient_ScrapeLowercaseMethod(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+	if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com", Method: "post", Body: "a=1"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScrapeConfig_CountryFallback(t *testing.T) {
+	cfg := &ScrapeConfig{URL: "https://example.com", Country: "US", CountryFallback: []string{"ca", "us", "MX"}}
+	params, err := cfg.toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := params.Get("country"); got != "us,ca,mx" {
+		t.Errorf("country = %q, want us,ca,mx", got)
+	}
+
+	params, err = (&ScrapeConfig{URL: "https://example.com", CountryFallback: []string{"de", "fr"}}).toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := params.Get("country"); got != "de,fr" {
+		t.Errorf("country = %q, want de,fr", got)
+	}
+
+	for _, fallback := range [][]string{{""}, {"usa"}, {"u1"}} {
+		cfg := &ScrapeConfig{URL: "https://example.com", Country: "us", CountryFallback: fallback}
+		if err := cfg.Validate(); !errors.Is(err, ErrScrapeConfig) {
+			t.Errorf("fallback %q: expected ErrScrapeConfig, got %v", fallback, err)
+		}
+	}
+}
+
+func TestScrapeConfig_CompressUpstream(t *testing.T) {
+	params, err := (&ScrapeConfig{URL: "https://example.com", CompressUpstream: true}).toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := params.Get("headers[accept-encoding]"); got != "gzip, deflate, br" {
+		t.Errorf("headers[accept-encoding] = %q", got)
+	}
+
+	cfg := &ScrapeConfig{URL: "https://example.com", CompressUpstream: true, HeadersMulti: http.Header{"Accept-Encoding": {"gzip"}}}
+	if params, err = cfg.toAPIParamsWithValidation(); err != nil {
+		t.Fatal(err)
+	}
+	if got := params["headers[accept-encoding]"]; len(got) != 1 || got[0] != "gzip" {
+		t.Errorf("headers[accept-encoding] = %q, want the caller's value only", got)
+	}
+}
+
+func TestClient_ScrapeUpstreamAcceptEncoding(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("headers[accept-encoding]"); got != "br" {
+			t.Errorf("upstream accept-encoding = %q, want br", got)
+		}
+		// The transport negotiates the API response encoding itself.
+		if got := r.Header.Get("Accept-Encoding"); got != "gzip" {
+			t.Errorf("API request Accept-Encoding = %q, want gzip", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		_, _ = zw.Write([]byte(`{"context": {"bandwidth_consumed": 1}, "result": {"success": true, "status": "DONE", "status_code": 200, "content": "ok", "content_type": "text/html"}}`))
+		_ = zw.Close()
+	})
+
+	result, err := client.Scrape(&ScrapeConfig{URL: "https://example.com", Headers: map[string]string{"Accept-Encoding": "br"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Result.Content != "ok" {
+		t.Errorf("content = %q", result.Result.Content)
+	}
+	if got := result.BandwidthSaved(); got != 1 {
+		t.Errorf("BandwidthSaved() = %d, want 1", got)
+	}
+}
+
+func TestScrapeConfig_OrderedHeaders(t *testing.T) {
+	var rawQuery string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+
+	cfg := &ScrapeConfig{
+		URL:     "https://example.com",
+		Headers: map[string]string{"X-Zzz": "1", "User-Agent": "replaced"},
+		OrderedHeaders: []OrderedHeader{
+			{Name: "User-Agent", Value: "Mozilla/5.0"},
+			{Name: "Accept", Value: "text/html"},
+			{Name: "Accept-Language", Value: "en-US"},
+			{Name: "Accept", Value: "*/*"},
+		},
+	}
+	params, err := cfg.toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := params["headers[accept]"]; len(got) != 2 || got[0] != "text/html" || got[1] != "*/*" {
+		t.Errorf("headers[accept] = %q", got)
+	}
+	if got := params.Get("headers[user-agent]"); got != "Mozilla/5.0" {
+		t.Errorf("headers[user-agent] = %q, want the ordered value", got)
+	}
+
+	if _, err := client.Scrape(cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"headers%5Buser-agent%5D=Mozilla", "headers%5Baccept%5D=text", "headers%5Baccept-language%5D=en-US", "headers%5Baccept%5D=%2A"}
+	last := strings.Index(rawQuery, "headers%5Bx-zzz%5D=1")
+	if last < 0 {
+		t.Fatalf("headers[x-zzz] missing from %s", rawQuery)
+	}
+	for _, prefix := range want {
+		i := strings.Index(rawQuery[last+1:], prefix)
+		if i < 0 {
+			t.Fatalf("%s missing or out of order in %s", prefix, rawQuery)
+		}
+		last += 1 + i
+	}
+	if strings.Count(rawQuery, "headers%5Buser-agent%5D") != 1 {
+		t.Errorf("user-agent sent twice: %s", rawQuery)
+	}
+
+	cfg = &ScrapeConfig{URL: "https://example.com", OrderedHeaders: []OrderedHeader{{Name: "Accept"}}}
+	if err := cfg.Validate(); !errors.Is(err, ErrScrapeConfig) {
+		t.Errorf("expected ErrScrapeConfig for empty value, got %v", err)
+	}
+}
+
+func TestScrapeConfig_OrderedCookieKeepsCookies(t *testing.T) {
+	cfg := &ScrapeConfig{
+		URL:            "https://example.com",
+		Cookies:        map[string]string{"session": "abc"},
+		OrderedHeaders: []OrderedHeader{{Name: "Accept", Value: "*/*"}, {Name: "Cookie", Value: "theme=dark"}},
+	}
+	params, err := cfg.toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := params["headers[cookie]"]; len(got) != 1 || got[0] != "theme=dark; session=abc" {
+		t.Errorf("headers[cookie] = %q", got)
+	}
+	query, err := url.ParseQuery(cfg.encodeAPIParams(params))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := query["headers[cookie]"]; len(got) != 1 || got[0] != "theme=dark; session=abc" {
+		t.Errorf("encoded headers[cookie] = %q", got)
+	}
+	if cfg.OrderedHeaders[1].Value != "theme=dark" {
+		t.Errorf("OrderedHeaders modified: %q", cfg.OrderedHeaders[1].Value)
+	}
+}
+
+func TestScrapeConfig_Clone(t *testing.T) {
+	sticky := true
+	cfg := &ScrapeConfig{
+		URL:                         "https://example.com",
+		BodyBytes:                   []byte{1, 2},
+		Headers:                     map[string]string{"X-A": "1"},
+		HeadersMulti:                http.Header{"Accept": {"text/html"}},
+		OrderedHeaders:              []OrderedHeader{{Name: "X-B", Value: "2"}},
+		Tags:                        []string{"a"},
+		SessionStickyProxy:          &sticky,
+		ExtractionEphemeralTemplate: map[string]interface{}{"selectors": []interface{}{map[string]interface{}{"name": "title"}}},
+		ScreenshotFlags:             []ScreenshotFlag{DarkMode},
+		JSScenario:                  []map[string]any{{"click": map[string]any{"selector": "#a"}}},
+	}
+	clone := cfg.Clone()
+
+	clone.BodyBytes[0] = 9
+	clone.Headers["X-A"] = "changed"
+	clone.HeadersMulti.Add("Accept", "*/*")
+	clone.OrderedHeaders[0].Value = "changed"
+	clone.Tags[0] = "changed"
+	*clone.SessionStickyProxy = false
+	clone.ExtractionEphemeralTemplate["selectors"].([]interface{})[0].(map[string]interface{})["name"] = "changed"
+	clone.ScreenshotFlags[0] = HighQuality
+	clone.JSScenario[0]["click"].(map[string]any)["selector"] = "#changed"
+
+	if cfg.BodyBytes[0] != 1 || cfg.Headers["X-A"] != "1" || len(cfg.HeadersMulti["Accept"]) != 1 ||
+		cfg.OrderedHeaders[0].Value != "2" || cfg.Tags[0] != "a" || !*cfg.SessionStickyProxy {
+		t.Errorf("clone shares state with the original: %+v", cfg)
+	}
+	if name := cfg.ExtractionEphemeralTemplate["selectors"].([]interface{})[0].(map[string]interface{})["name"]; name != "title" {
+		t.Errorf("ephemeral template shared, name = %v", name)
+	}
+	if flag := cfg.ScreenshotFlags[0]; flag != DarkMode {
+		t.Errorf("screenshot flags shared, flag = %v", flag)
+	}
+	if selector := cfg.JSScenario[0]["click"].(map[string]any)["selector"]; selector != "#a" {
+		t.Errorf("js scenario shared, selector = %v", selector)
+	}
+	if clone.URL != cfg.URL {
+		t.Errorf("clone URL = %q", clone.URL)
+	}
+}
+
+func TestScrapeConfig_AutoScrollTuning(t *testing.T) {
+	cfg := &ScrapeConfig{
+		URL:             "https://web-scraping.dev/testimonials",
+		RenderJS:        true,
+		AutoScroll:      true,
+		AutoScrollCount: 2,
+		AutoScrollDelay: 500,
+		JSScenario:      []map[string]any{{"click": map[string]any{"selector": "#accept"}}},
+	}
+	params, err := cfg.toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Get("auto_scroll") != "true" {
+		t.Errorf("auto_scroll = %q", params.Get("auto_scroll"))
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(params.Get("js_scenario"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"click":{"selector":"#accept"}},{"scroll":{"selector":"bottom"}},{"wait":500},{"scroll":{"selector":"bottom"}},{"wait":500}]`
+	if string(raw) != want {
+		t.Errorf("js_scenario = %s, want %s", raw, want)
+	}
+	if len(cfg.JSScenario) != 1 {
+		t.Errorf("JSScenario was modified: %v", cfg.JSScenario)
+	}
+
+	for _, bad := range []*ScrapeConfig{
+		{URL: "https://example.com", AutoScrollCount: 2},
+		{URL: "https://example.com", RenderJS: true, AutoScrollCount: 2},
+		{URL: "https://example.com", RenderJS: true, AutoScroll: true, AutoScrollDelay: 100},
+		{URL: "https://example.com", RenderJS: true, AutoScroll: true, AutoScrollCount: -1},
+	} {
+		if err := bad.Validate(); !errors.Is(err, ErrScrapeConfig) {
+			t.Errorf("%+v: expected ErrScrapeConfig, got %v", bad, err)
+		}
+	}
+}
+
+func TestScrapeConfig_SetGraphQL(t *testing.T) {
+	cfg := &ScrapeConfig{
+		URL:     "https://web-scraping.dev/api/graphql",
+		Headers: map[string]string{"Content-Type": "text/plain", "x-api": "1"},
+		Data:    map[string]interface{}{"stale": true},
+	}
+	query := `query Reviews($first: Int) { reviews(first: $first) { edges { node { text } } } }`
+	if err := cfg.SetGraphQL(query, map[string]interface{}{"first": 10}); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Method != HttpMethodPost || cfg.Data != nil {
+		t.Errorf("method = %q, data = %v", cfg.Method, cfg.Data)
+	}
+	if !reflect.DeepEqual(cfg.Headers, map[string]string{"content-type": "application/json", "x-api": "1"}) {
+		t.Errorf("headers = %v", cfg.Headers)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(cfg.Body), &body); err != nil {
+		t.Fatalf("body %q is not JSON: %v", cfg.Body, err)
+	}
+	want := map[string]interface{}{"query": query, "variables": map[string]interface{}{"first": float64(10)}}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("body = %v, want %v", body, want)
+	}
+	if err := cfg.processBody(); err != nil {
+		t.Errorf("processBody() = %v", err)
+	}
+
+	if err := cfg.SetGraphQL("{ viewer { id } }", nil); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Body != `{"query":"{ viewer { id } }"}` {
+		t.Errorf("body without variables = %s", cfg.Body)
+	}
+	if err := cfg.SetGraphQL("{}", map[string]interface{}{"bad": make(chan int)}); !errors.Is(err, ErrScrapeConfig) {
+		t.Errorf("expected ErrScrapeConfig for unencodable variables, got %v", err)
+	}
+}
+
+func TestScrapeConfig_RenderingStage(t *testing.T) {
+	cfg := &ScrapeConfig{URL: "https://example.com", RenderJS: true, RenderingStage: string(RenderingStageDOMContentLoaded), RenderingWait: 2000}
+	params, err := cfg.toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Get("rendering_stage") != "domcontentloaded" || params.Get("rendering_wait") != "2000" {
+		t.Errorf("rendering_stage = %q, rendering_wait = %q", params.Get("rendering_stage"), params.Get("rendering_wait"))
+	}
+
+	for _, stage := range []string{"networkidle", "load", "Complete"} {
+		cfg := &ScrapeConfig{URL: "https://example.com", RenderJS: true, RenderingStage: stage}
+		if err := cfg.Validate(); !errors.Is(err, ErrScrapeConfig) {
+			t.Errorf("stage %q: expected ErrScrapeConfig, got %v", stage, err)
+		}
+	}
+}
+
+func TestScrapeConfig_ExtractionRejectsHEAD(t *testing.T) {
+	cfg := &ScrapeConfig{URL: "https://example.com", Method: HttpMethodHead, ExtractionModel: ExtractionModelProduct}
+	if err := cfg.Validate(); !errors.Is(err, ErrScrapeConfig) {
+		t.Errorf("expected ErrScrapeConfig, got %v", err)
+	}
+	cfg.Method = HttpMethodGet
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("GET with extraction rejected: %v", err)
+	}
+}
+
+func TestScrapeConfig_InvalidEnums(t *testing.T) {
+	for name, cfg := range map[string]*ScrapeConfig{
+		"Format":          {Format: "yaml"},
+		"FormatOptions":   {Format: FormatMarkdown, FormatOptions: []FormatOption{NoLinks, "no_tables"}},
+		"ProxyPool":       {ProxyPool: "public_satellite_pool"},
+		"ExtractionModel": {ExtractionModel: "recipe"},
+		"ScreenshotFlags": {RenderJS: true, Screenshots: map[string]string{"page": "fullpage"}, ScreenshotFlags: []ScreenshotFlag{"sepia"}},
+		"Method":          {Method: "BREW"},
+		"RenderingStage":  {RenderJS: true, RenderingStage: "networkidle"},
+	} {
+		cfg.URL = "https://example.com"
+		if _, err := cfg.toAPIParamsWithValidation(); !errors.Is(err, ErrScrapeConfig) {
+			t.Errorf("%s: expected ErrScrapeConfig, got %v", name, err)
+		}
+	}
+}
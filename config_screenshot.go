@@ -3,6 +3,7 @@ package scrapfly
 import (
 	"fmt"
 	"net/url"
+	"slices"
 	"strings"
 )
 
@@ -19,6 +20,9 @@ const (
 	FormatWEBP ScreenshotFormat = "webp"
 	// FormatGIF captures screenshots in GIF format (animated screenshots support).
 	FormatGIF ScreenshotFormat = "gif"
+
+	// screenshotFormatPDF is requested by Client.RenderPDF.
+	screenshotFormatPDF ScreenshotFormat = "pdf"
 )
 
 // ScreenshotOption defines options to customize screenshot capture behavior.
@@ -86,6 +90,19 @@ type ScreenshotConfig struct {
 	VisionDeficiencyType VisionDeficiencyType
 }
 
+// Clone returns a deep copy of the config, so variants (another resolution,
+// another format) can be derived without sharing Options with c.
+//
+// Example:
+//
+//	mobile := config.Clone()
+//	mobile.Resolution = "390x844"
+func (c *ScreenshotConfig) Clone() *ScreenshotConfig {
+	clone := *c
+	clone.Options = slices.Clone(c.Options)
+	return &clone
+}
+
 // toAPIParams converts the ScreenshotConfig into URL parameters for the Scrapfly API.
 // This is an internal method used by the Client to prepare API requests.
 func (c *ScreenshotConfig) toAPIParams() (url.Values, error) {
@@ -152,3 +169,46 @@ func (c *ScreenshotConfig) toAPIParams() (url.Values, error) {
 
 	return params, nil
 }
+
+// PDFConfig configures a PDF export of a rendered page, see Client.RenderPDF.
+//
+// Example:
+//
+//	config := &scrapfly.PDFConfig{
+//	    URL:             "https://example.com/invoice/42",
+//	    WaitForSelector: "#total",
+//	    Options:         []scrapfly.ScreenshotOption{scrapfly.OptionPrintMediaFormat},
+//	}
+type PDFConfig struct {
+	// URL is the target URL to render (required).
+	URL string
+	// Country specifies the proxy country code (e.g., "us", "uk", "de").
+	Country string
+	// Timeout sets the maximum time in milliseconds to wait for the request.
+	Timeout int
+	// RenderingWait is additional wait time in milliseconds after page load.
+	RenderingWait int
+	// WaitForSelector waits for a CSS selector to appear before rendering.
+	WaitForSelector string
+	// Options are additional rendering options (print media format, block banners, etc.).
+	Options []ScreenshotOption
+	// AutoScroll automatically scrolls the page to load lazy content.
+	AutoScroll bool
+	// JS is custom JavaScript code to execute before rendering.
+	JS string
+}
+
+// toScreenshotConfig maps the PDFConfig onto the screenshot API it is served by.
+func (c *PDFConfig) toScreenshotConfig() *ScreenshotConfig {
+	return &ScreenshotConfig{
+		URL:             c.URL,
+		Format:          screenshotFormatPDF,
+		Country:         c.Country,
+		Timeout:         c.Timeout,
+		RenderingWait:   c.RenderingWait,
+		WaitForSelector: c.WaitForSelector,
+		Options:         c.Options,
+		AutoScroll:      c.AutoScroll,
+		JS:              c.JS,
+	}
+}
@@ -0,0 +1,65 @@
+package scrapfly
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestClient_RenderPDF(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/screenshot" || r.URL.Query().Get("format") != "pdf" {
+			t.Errorf("unexpected request %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+		if r.URL.Query().Get("wait_for_selector") != "#total" {
+			t.Errorf("wait_for_selector not forwarded")
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		_, _ = w.Write([]byte("%PDF-1.7\n%fake document"))
+	})
+
+	pdf, err := client.RenderPDF(&PDFConfig{URL: "https://example.com", WaitForSelector: "#total"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(pdf.Data, []byte("%PDF-")) {
+		t.Errorf("data does not start with the PDF magic bytes: %q", pdf.Data)
+	}
+	path, err := pdf.Save("page", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Ext(path) != ".pdf" {
+		t.Errorf("saved as %s", path)
+	}
+}
+
+func TestClient_RenderPDFRejectsNonPDF(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("\x89PNG"))
+	})
+	if _, err := client.RenderPDF(&PDFConfig{URL: "https://example.com"}); !errors.Is(err, ErrScreenshotAPIFailed) {
+		t.Fatalf("expected ErrScreenshotAPIFailed, got %v", err)
+	}
+}
+
+func TestScreenshotConfig_Clone(t *testing.T) {
+	cfg := &ScreenshotConfig{
+		URL:        "https://example.com",
+		Resolution: "1920x1080",
+		Options:    []ScreenshotOption{OptionDarkMode},
+	}
+	mobile := cfg.Clone()
+	mobile.Resolution = "390x844"
+	mobile.Options[0] = OptionBlockBanners
+
+	if cfg.Resolution != "1920x1080" || cfg.Options[0] != OptionDarkMode {
+		t.Errorf("clone shares state with the original: %+v", cfg)
+	}
+	if mobile.URL != cfg.URL {
+		t.Errorf("clone = %+v", mobile)
+	}
+}
@@ -60,9 +60,9 @@ func (c *Client) StartCrawl(config *CrawlerConfig) (*CrawlerStartResponse, error
 		return nil, err
 	}
 
-	endpointURL, _ := url.Parse(c.host + "/crawl")
+	endpointURL, _ := url.Parse(c.endpoint("/crawl"))
 	q := url.Values{}
-	q.Set("key", c.key)
+	q.Set("key", c.APIKey())
 	endpointURL.RawQuery = q.Encode()
 
 	req, err := http.NewRequest("POST", endpointURL.String(), bytes.NewReader(body))
@@ -73,11 +73,11 @@ func (c *Client) StartCrawl(config *CrawlerConfig) (*CrawlerStartResponse, error
 	req.GetBody = func() (io.ReadCloser, error) {
 		return io.NopCloser(bytes.NewReader(body)), nil
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := fetchWithRetry(c.httpClient, req, defaultRetries, defaultDelay)
+	resp, err := fetchWithRetry(c, req, defaultRetries, defaultDelay)
 	if err != nil {
 		return nil, err
 	}
@@ -111,19 +111,19 @@ func (c *Client) CrawlStatus(uuid string) (*CrawlerStatus, error) {
 		return nil, fmt.Errorf("%w: uuid must be a non-empty string", ErrCrawlerConfig)
 	}
 
-	endpointURL, _ := url.Parse(c.host + "/crawl/" + url.PathEscape(uuid) + "/status")
+	endpointURL, _ := url.Parse(c.endpoint("/crawl/" + url.PathEscape(uuid) + "/status"))
 	q := url.Values{}
-	q.Set("key", c.key)
+	q.Set("key", c.APIKey())
 	endpointURL.RawQuery = q.Encode()
 
 	req, err := http.NewRequest("GET", endpointURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := fetchWithRetry(c.httpClient, req, defaultRetries, defaultDelay)
+	resp, err := fetchWithRetry(c, req, defaultRetries, defaultDelay)
 	if err != nil {
 		return nil, err
 	}
@@ -184,9 +184,9 @@ func (c *Client) CrawlURLs(uuid string, opts *CrawlURLsOptions) (*CrawlerURLs, e
 		statusHint = "visited"
 	}
 
-	endpointURL, _ := url.Parse(c.host + "/crawl/" + url.PathEscape(uuid) + "/urls")
+	endpointURL, _ := url.Parse(c.endpoint("/crawl/" + url.PathEscape(uuid) + "/urls"))
 	q := url.Values{}
-	q.Set("key", c.key)
+	q.Set("key", c.APIKey())
 	q.Set("page", strconv.Itoa(page))
 	q.Set("per_page", strconv.Itoa(perPage))
 	if opts.Status != "" {
@@ -198,12 +198,12 @@ func (c *Client) CrawlURLs(uuid string, opts *CrawlURLsOptions) (*CrawlerURLs, e
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 	// text/plain is canonical; also accept JSON because error envelopes come
 	// back as JSON regardless of the success response type.
 	req.Header.Set("Accept", "text/plain, application/json")
 
-	resp, err := fetchWithRetry(c.httpClient, req, defaultRetries, defaultDelay)
+	resp, err := fetchWithRetry(c, req, defaultRetries, defaultDelay)
 	if err != nil {
 		return nil, err
 	}
@@ -316,9 +316,9 @@ func (c *Client) crawlContentsRaw(uuid string, opts *CrawlContentsOptions) ([]by
 		return nil, "", fmt.Errorf("%w: invalid format %q", ErrCrawlerConfig, opts.Format)
 	}
 
-	endpointURL, _ := url.Parse(c.host + "/crawl/" + url.PathEscape(uuid) + "/contents")
+	endpointURL, _ := url.Parse(c.endpoint("/crawl/" + url.PathEscape(uuid) + "/contents"))
 	q := url.Values{}
-	q.Set("key", c.key)
+	q.Set("key", c.APIKey())
 	// Server query param is `formats` (plural), not `format`. The public docs
 	// say `format` but the actual server only accepts `formats` — discovered
 	// during the TS/Python SDK port.
@@ -341,7 +341,7 @@ func (c *Client) crawlContentsRaw(uuid string, opts *CrawlContentsOptions) ([]by
 	if err != nil {
 		return nil, "", err
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 	if opts.Plain {
 		// Plain mode: server picks content-type based on format.
 		req.Header.Set("Accept", "*/*")
@@ -349,7 +349,7 @@ func (c *Client) crawlContentsRaw(uuid string, opts *CrawlContentsOptions) ([]by
 		req.Header.Set("Accept", "application/json")
 	}
 
-	resp, err := fetchWithRetry(c.httpClient, req, defaultRetries, defaultDelay)
+	resp, err := fetchWithRetry(c, req, defaultRetries, defaultDelay)
 	if err != nil {
 		return nil, "", err
 	}
@@ -393,9 +393,9 @@ func (c *Client) CrawlContentsBatch(uuid string, urls []string, formats []Crawle
 		}
 	}
 
-	endpointURL, _ := url.Parse(c.host + "/crawl/" + url.PathEscape(uuid) + "/contents/batch")
+	endpointURL, _ := url.Parse(c.endpoint("/crawl/" + url.PathEscape(uuid) + "/contents/batch"))
 	q := url.Values{}
-	q.Set("key", c.key)
+	q.Set("key", c.APIKey())
 	formatStrs := make([]string, len(formats))
 	for i, f := range formats {
 		formatStrs[i] = string(f)
@@ -411,11 +411,11 @@ func (c *Client) CrawlContentsBatch(uuid string, urls []string, formats []Crawle
 	req.GetBody = func() (io.ReadCloser, error) {
 		return io.NopCloser(bytes.NewReader(body)), nil
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 	req.Header.Set("Content-Type", "text/plain")
 	req.Header.Set("Accept", "multipart/related, application/json")
 
-	resp, err := fetchWithRetry(c.httpClient, req, defaultRetries, defaultDelay)
+	resp, err := fetchWithRetry(c, req, defaultRetries, defaultDelay)
 	if err != nil {
 		return nil, err
 	}
@@ -454,19 +454,19 @@ func (c *Client) CrawlCancel(uuid string) error {
 		return fmt.Errorf("%w: uuid must be a non-empty string", ErrCrawlerConfig)
 	}
 
-	endpointURL, _ := url.Parse(c.host + "/crawl/" + url.PathEscape(uuid) + "/cancel")
+	endpointURL, _ := url.Parse(c.endpoint("/crawl/" + url.PathEscape(uuid) + "/cancel"))
 	q := url.Values{}
-	q.Set("key", c.key)
+	q.Set("key", c.APIKey())
 	endpointURL.RawQuery = q.Encode()
 
 	req, err := http.NewRequest("POST", endpointURL.String(), nil)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := fetchWithRetry(c.httpClient, req, defaultRetries, defaultDelay)
+	resp, err := fetchWithRetry(c, req, defaultRetries, defaultDelay)
 	if err != nil {
 		return err
 	}
@@ -495,9 +495,9 @@ func (c *Client) CrawlArtifact(uuid string, artifactType CrawlerArtifactType) (*
 		return nil, fmt.Errorf("%w: artifact type must be 'warc' or 'har', got %q", ErrCrawlerConfig, artifactType)
 	}
 
-	endpointURL, _ := url.Parse(c.host + "/crawl/" + url.PathEscape(uuid) + "/artifact")
+	endpointURL, _ := url.Parse(c.endpoint("/crawl/" + url.PathEscape(uuid) + "/artifact"))
 	q := url.Values{}
-	q.Set("key", c.key)
+	q.Set("key", c.APIKey())
 	q.Set("type", string(artifactType))
 	endpointURL.RawQuery = q.Encode()
 
@@ -505,7 +505,7 @@ func (c *Client) CrawlArtifact(uuid string, artifactType CrawlerArtifactType) (*
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 	// Accept both the binary artifact types and JSON for error envelopes.
 	if artifactType == ArtifactTypeHAR {
 		req.Header.Set("Accept", "application/json, application/octet-stream")
@@ -513,7 +513,7 @@ func (c *Client) CrawlArtifact(uuid string, artifactType CrawlerArtifactType) (*
 		req.Header.Set("Accept", "application/gzip, application/octet-stream, application/json")
 	}
 
-	resp, err := fetchWithRetry(c.httpClient, req, defaultRetries, defaultDelay)
+	resp, err := fetchWithRetry(c, req, defaultRetries, defaultDelay)
 	if err != nil {
 		return nil, err
 	}
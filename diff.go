@@ -0,0 +1,321 @@
+package scrapfly
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// DiffOptions tunes DiffContent.
+type DiffOptions struct {
+	// Selector scopes the diff to the text of the elements matching this
+	// CSS selector, e.g. ".price" or "#changelog". Both results must be
+	// HTML. When empty, the whole text content of the page is compared.
+	Selector string
+	// Context is the number of unchanged lines shown around each change in
+	// ContentDiff.Unified. Defaults to 3.
+	Context int
+}
+
+// DiffOp is the kind of a DiffLine.
+type DiffOp int
+
+const (
+	// DiffEqual marks a line present in both contents.
+	DiffEqual DiffOp = iota
+	// DiffRemoved marks a line only present in the first content.
+	DiffRemoved
+	// DiffAdded marks a line only present in the second content.
+	DiffAdded
+)
+
+// DiffLine is one line of a ContentDiff.
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// ContentDiff is a line diff of the text content of two scrapes, see
+// DiffContent.
+type ContentDiff struct {
+	// Lines is the full edit script turning the first content into the
+	// second one, in order.
+	Lines []DiffLine
+	// Added lists the lines only present in the second content.
+	Added []string
+	// Removed lists the lines only present in the first content.
+	Removed []string
+
+	fromName, toName string
+	context          int
+}
+
+// maxDiffCells caps the size of the table used to compute a minimal diff
+// of the lines that differ between the two contents. Larger diffs fall
+// back to comparing the lines as sets, see DiffContent.
+const maxDiffCells = 1 << 22
+
+// DiffContent compares the text content of two scrapes of (usually) the
+// same page, line by line, ignoring blank lines and surrounding whitespace.
+// HTML pages are compared on their text, so markup-only changes don't show
+// up; other content types are compared as is. opts.Selector scopes the
+// diff to the matching elements, using Selector, and returns ErrContentType
+// when a result is not HTML.
+//
+// Very large diffs (millions of line pairs) are not minimal: the changed
+// region is then compared as sets of lines, which still reports every
+// added and removed line.
+//
+// Example:
+//
+//	diff, err := scrapfly.DiffContent(previous, current, scrapfly.DiffOptions{Selector: ".product"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if diff.Changed() {
+//	    fmt.Print(diff.Unified())
+//	}
+func DiffContent(a, b *ScrapeResult, opts DiffOptions) (*ContentDiff, error) {
+	from, err := diffLines(a, opts.Selector)
+	if err != nil {
+		return nil, err
+	}
+	to, err := diffLines(b, opts.Selector)
+	if err != nil {
+		return nil, err
+	}
+	context := opts.Context
+	if context <= 0 {
+		context = 3
+	}
+	diff := &ContentDiff{Lines: diffEditScript(from, to), fromName: a.FinalURL(), toName: b.FinalURL(), context: context}
+	for _, line := range diff.Lines {
+		switch line.Op {
+		case DiffAdded:
+			diff.Added = append(diff.Added, line.Text)
+		case DiffRemoved:
+			diff.Removed = append(diff.Removed, line.Text)
+		}
+	}
+	return diff, nil
+}
+
+// Changed reports whether the contents differ.
+func (d *ContentDiff) Changed() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0
+}
+
+// Unified renders the diff in the unified diff format, with the result
+// URLs as file names. It returns "" when the contents are identical.
+func (d *ContentDiff) Unified() string {
+	if !d.Changed() {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", d.fromName, d.toName)
+
+	// fromLine and toLine are the 1-based line numbers at which each entry
+	// of Lines starts in the first and second content.
+	fromLine := make([]int, len(d.Lines)+1)
+	toLine := make([]int, len(d.Lines)+1)
+	fromLine[0], toLine[0] = 1, 1
+	for i, line := range d.Lines {
+		fromLine[i+1], toLine[i+1] = fromLine[i], toLine[i]
+		if line.Op != DiffAdded {
+			fromLine[i+1]++
+		}
+		if line.Op != DiffRemoved {
+			toLine[i+1]++
+		}
+	}
+
+	for i := 0; i < len(d.Lines); {
+		if d.Lines[i].Op == DiffEqual {
+			i++
+			continue
+		}
+		// Grow the hunk until the next change is more than two contexts away.
+		start := max(0, i-d.context)
+		end := i
+		for end < len(d.Lines) {
+			if d.Lines[end].Op != DiffEqual {
+				end++
+				continue
+			}
+			next := end
+			for next < len(d.Lines) && d.Lines[next].Op == DiffEqual {
+				next++
+			}
+			if next == len(d.Lines) || next-end > 2*d.context {
+				break
+			}
+			end = next
+		}
+		end = min(len(d.Lines), end+d.context)
+
+		fromStart, fromCount := fromLine[start], fromLine[end]-fromLine[start]
+		toStart, toCount := toLine[start], toLine[end]-toLine[start]
+		if fromCount == 0 {
+			fromStart--
+		}
+		if toCount == 0 {
+			toStart--
+		}
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", fromStart, fromCount, toStart, toCount)
+		for _, line := range d.Lines[start:end] {
+			switch line.Op {
+			case DiffEqual:
+				b.WriteString(" ")
+			case DiffRemoved:
+				b.WriteString("-")
+			case DiffAdded:
+				b.WriteString("+")
+			}
+			b.WriteString(line.Text)
+			b.WriteString("\n")
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// diffLines returns the non-blank, trimmed lines of the text content of r,
+// scoped to selector when set.
+func diffLines(r *ScrapeResult, selector string) ([]string, error) {
+	var text string
+	switch {
+	case selector != "":
+		doc, err := r.Selector()
+		if err != nil {
+			return nil, err
+		}
+		var parts []string
+		doc.Find(selector).Each(func(_ int, s *goquery.Selection) {
+			parts = append(parts, s.Text())
+		})
+		text = strings.Join(parts, "\n")
+	case strings.Contains(r.Result.ContentType, "text/html"):
+		doc, err := r.Selector()
+		if err != nil {
+			return nil, err
+		}
+		// Work on a copy: the document is cached and shared with the other
+		// helpers, JSONLD needs its scripts.
+		page := doc.Selection.Clone()
+		page.Find("script, style, noscript, template").Remove()
+		text = page.Text()
+	default:
+		text = r.Result.Content
+	}
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// diffEditScript returns the edit script turning from into to. The common
+// prefix and suffix are matched first; the lines in between are diffed
+// with a longest common subsequence table, or as sets when that table
+// would exceed maxDiffCells.
+func diffEditScript(from, to []string) []DiffLine {
+	prefix := 0
+	for prefix < len(from) && prefix < len(to) && from[prefix] == to[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(from)-prefix && suffix < len(to)-prefix && from[len(from)-1-suffix] == to[len(to)-1-suffix] {
+		suffix++
+	}
+
+	script := make([]DiffLine, 0, len(from)+len(to))
+	for _, line := range from[:prefix] {
+		script = append(script, DiffLine{DiffEqual, line})
+	}
+	fromMid, toMid := from[prefix:len(from)-suffix], to[prefix:len(to)-suffix]
+	if (len(fromMid)+1)*(len(toMid)+1) <= maxDiffCells {
+		script = append(script, lcsEditScript(fromMid, toMid)...)
+	} else {
+		script = append(script, setEditScript(fromMid, toMid)...)
+	}
+	for _, line := range from[len(from)-suffix:] {
+		script = append(script, DiffLine{DiffEqual, line})
+	}
+	return script
+}
+
+// lcsEditScript diffs from and to using a longest common subsequence table.
+func lcsEditScript(from, to []string) []DiffLine {
+	n, m := len(from), len(to)
+	// lcs[i][j] is the LCS length of from[i:] and to[j:].
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var script []DiffLine
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case from[i] == to[j]:
+			script = append(script, DiffLine{DiffEqual, from[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			script = append(script, DiffLine{DiffRemoved, from[i]})
+			i++
+		default:
+			script = append(script, DiffLine{DiffAdded, to[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		script = append(script, DiffLine{DiffRemoved, from[i]})
+	}
+	for ; j < m; j++ {
+		script = append(script, DiffLine{DiffAdded, to[j]})
+	}
+	return script
+}
+
+// setEditScript diffs from and to as multisets of lines: lines of from
+// missing from to are removed, the others are kept, then the lines of to
+// missing from from are added.
+func setEditScript(from, to []string) []DiffLine {
+	remaining := make(map[string]int, len(to))
+	for _, line := range to {
+		remaining[line]++
+	}
+	kept := make(map[string]int, len(from))
+	script := make([]DiffLine, 0, len(from)+len(to))
+	for _, line := range from {
+		if remaining[line] > 0 {
+			remaining[line]--
+			kept[line]++
+			script = append(script, DiffLine{DiffEqual, line})
+		} else {
+			script = append(script, DiffLine{DiffRemoved, line})
+		}
+	}
+	for _, line := range to {
+		if kept[line] > 0 {
+			kept[line]--
+			continue
+		}
+		script = append(script, DiffLine{DiffAdded, line})
+	}
+	return script
+}
@@ -0,0 +1,122 @@
+package scrapfly
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffContent(t *testing.T) {
+	a := htmlResult("https://web-scraping.dev/product/1", `<html><head><script>var v = 1;</script></head><body>
+<h1>Box of Chocolate Candy</h1>
+<p class="price">$9.99</p>
+<p>In stock</p>
+<p>Free shipping</p>
+</body></html>`)
+	b := htmlResult("https://web-scraping.dev/product/1", `<html><head><script>var v = 2;</script></head><body>
+<h1>Box of Chocolate Candy</h1>
+<p class="price">$8.99</p>
+<p>In stock</p>
+<p>Free shipping</p>
+<p>New flavor!</p>
+</body></html>`)
+
+	diff, err := DiffContent(a, b, DiffOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Changed() {
+		t.Fatal("expected a change")
+	}
+	if !reflect.DeepEqual(diff.Removed, []string{"$9.99"}) || !reflect.DeepEqual(diff.Added, []string{"$8.99", "New flavor!"}) {
+		t.Errorf("removed = %q, added = %q", diff.Removed, diff.Added)
+	}
+	want := `--- https://web-scraping.dev/product/1
++++ https://web-scraping.dev/product/1
+@@ -1,4 +1,5 @@
+ Box of Chocolate Candy
+-$9.99
++$8.99
+ In stock
+ Free shipping
++New flavor!
+`
+	if got := diff.Unified(); got != want {
+		t.Errorf("Unified() =\n%s\nwant\n%s", got, want)
+	}
+
+	scoped, err := DiffContent(a, b, DiffOptions{Selector: ".price"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(scoped.Removed, []string{"$9.99"}) || !reflect.DeepEqual(scoped.Added, []string{"$8.99"}) {
+		t.Errorf("scoped removed = %q, added = %q", scoped.Removed, scoped.Added)
+	}
+
+	same, err := DiffContent(a, a, DiffOptions{Selector: "h1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same.Changed() || same.Unified() != "" {
+		t.Errorf("identical contents reported as changed: %+v", same)
+	}
+
+	// The cached document is left untouched.
+	if n := func() int { doc, _ := a.Selector(); return doc.Find("script").Length() }(); n != 1 {
+		t.Errorf("script elements after diff = %d, want 1", n)
+	}
+
+	text := &ScrapeResult{Result: ResultData{ContentType: "text/plain", Content: "a\nb"}}
+	if _, err := DiffContent(text, text, DiffOptions{Selector: "p"}); !errors.Is(err, ErrContentType) {
+		t.Errorf("expected ErrContentType, got %v", err)
+	}
+}
+
+func TestDiffContent_Hunks(t *testing.T) {
+	var fromLines, toLines []string
+	for i := 1; i <= 20; i++ {
+		line := "line " + strings.Repeat("x", i)
+		fromLines = append(fromLines, line)
+		switch i {
+		case 2:
+			toLines = append(toLines, "changed 2")
+		case 18:
+		default:
+			toLines = append(toLines, line)
+		}
+	}
+	a := &ScrapeResult{Result: ResultData{ContentType: "text/plain", Content: strings.Join(fromLines, "\n")}}
+	b := &ScrapeResult{Result: ResultData{ContentType: "text/plain", Content: strings.Join(toLines, "\n")}}
+	diff, err := DiffContent(a, b, DiffOptions{Context: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var headers []string
+	for _, line := range strings.Split(diff.Unified(), "\n") {
+		if strings.HasPrefix(line, "@@") {
+			headers = append(headers, line)
+		}
+	}
+	if want := []string{"@@ -1,3 +1,3 @@", "@@ -17,3 +17,2 @@"}; !reflect.DeepEqual(headers, want) {
+		t.Errorf("hunks = %q, want %q", headers, want)
+	}
+}
+
+func TestDiffEditScript_SetFallback(t *testing.T) {
+	from := []string{"a", "b", "c"}
+	to := []string{"c", "d", "a"}
+	script := setEditScript(from, to)
+	var added, removed []string
+	for _, line := range script {
+		switch line.Op {
+		case DiffAdded:
+			added = append(added, line.Text)
+		case DiffRemoved:
+			removed = append(removed, line.Text)
+		}
+	}
+	if !reflect.DeepEqual(removed, []string{"b"}) || !reflect.DeepEqual(added, []string{"d"}) {
+		t.Errorf("removed = %q, added = %q", removed, added)
+	}
+}
@@ -130,6 +130,25 @@
 //	    }
 //	}
 //
+// # Cancellation
+//
+// The Scrape API has no endpoint to cancel a scrape once it is submitted;
+// only crawls can be cancelled server-side, with Client.CrawlCancel.
+// Scrapes are cancelled client-side through a context: ScrapeWithContext,
+// ConcurrentScrapeWithContext and WaitForScrapeWithContext stop waiting,
+// retrying and polling as soon as the context is done, and queued
+// ConcurrentScrape configs are never sent. A scrape already received by the
+// API, including an asynchronous (webhook) one, still runs to completion and
+// is billed, so cancel early to save credits:
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	for item := range client.ConcurrentScrapeWithContext(ctx, configs, 5, scrapfly.ConcurrentScrapeOptions{}) {
+//	    if budgetExceeded() {
+//	        cancel() // remaining configs are skipped
+//	    }
+//	}
+//
 // # Debugging
 //
 // Enable debug logging to see detailed request information:
@@ -113,6 +113,38 @@ func (f ProxyPool) IsValid() bool {
 	return IsValidEnumType(f)
 }
 
+// RenderingStage is the page load event the browser waits for before the
+// rendering is considered done, see ScrapeConfig.RenderingStage.
+type RenderingStage string
+
+// Available rendering stages. The API has no network idle stage: for pages
+// that keep loading data after domcontentloaded, combine
+// RenderingStageComplete with ScrapeConfig.WaitForSelector or RenderingWait.
+const (
+	// RenderingStageComplete waits for the load event (the default).
+	RenderingStageComplete RenderingStage = "complete"
+	// RenderingStageDOMContentLoaded waits for the DOMContentLoaded event only.
+	RenderingStageDOMContentLoaded RenderingStage = "domcontentloaded"
+)
+
+func (f RenderingStage) Enum() []RenderingStage {
+	return []RenderingStage{RenderingStageComplete, RenderingStageDOMContentLoaded}
+}
+
+func (f RenderingStage) String() string {
+	if slices.Contains(f.Enum(), f) {
+		return string(f)
+	}
+	return "invalid_rendering_stage"
+}
+
+func (f RenderingStage) AnyEnum() []any {
+	return []any{RenderingStageComplete, RenderingStageDOMContentLoaded}
+}
+func (f RenderingStage) IsValid() bool {
+	return IsValidEnumType(f)
+}
+
 // ScreenshotFlag defines options for screenshot behavior when using Screenshots parameter.
 type ScreenshotFlag string
 
@@ -213,6 +245,17 @@ func (f FormatOption) IsValid() bool {
 	return IsValidEnumType(f)
 }
 
+// HttpMethod is the HTTP method the Scrape API uses against the target URL,
+// see ScrapeConfig.Method.
+//
+// Supported methods on the /scrape endpoint:
+//
+//	GET, HEAD, OPTIONS  no request body
+//	POST, PUT, PATCH    request body from ScrapeConfig.Body, BodyBytes or Data
+//	DELETE              not supported by the API
+//	TRACE, CONNECT      not supported by the API (404 on the scrape endpoint)
+//
+// Unsupported methods are rejected with ErrScrapeConfig before any request is made.
 type HttpMethod string
 
 const (
@@ -245,6 +288,37 @@ func (f HttpMethod) IsValid() bool {
 	return IsValidEnumType(f)
 }
 
+// OperatingSystem is an operating system the scrape fingerprint can spoof,
+// see ScrapeConfig.OS.
+type OperatingSystem string
+
+// Supported operating systems for ScrapeConfig.OS.
+const (
+	OSWindows  OperatingSystem = "windows"
+	OSMacOS    OperatingSystem = "macos"
+	OSLinux    OperatingSystem = "linux"
+	OSChromeOS OperatingSystem = "chromeos"
+)
+
+func (f OperatingSystem) Enum() []OperatingSystem {
+	return []OperatingSystem{OSWindows, OSMacOS, OSLinux, OSChromeOS}
+}
+
+func (f OperatingSystem) AnyEnum() []any {
+	return []any{OSWindows, OSMacOS, OSLinux, OSChromeOS}
+}
+
+func (f OperatingSystem) String() string {
+	if slices.Contains(f.Enum(), f) {
+		return string(f)
+	}
+	return "invalid_operating_system"
+}
+
+func (f OperatingSystem) IsValid() bool {
+	return IsValidEnumType(f)
+}
+
 type Enumerable[T fmt.Stringer] interface {
 	Enum() []T
 	AnyEnum() []any
@@ -3,6 +3,7 @@ package scrapfly
 import (
 	"errors"
 	"fmt"
+	"net/http"
 )
 
 // Sentinel errors for the Scrapfly client.
@@ -35,6 +36,16 @@ var (
 	// ErrExtractionAPIFailed indicates an extraction API error occurred.
 	ErrExtractionAPIFailed = errors.New("extraction API error")
 
+	// ErrResponseTooLarge indicates a response exceeded the client's MaxResponseBytes limit.
+	ErrResponseTooLarge = errors.New("response too large")
+
+	// ErrExtractionTimeout indicates an asynchronous extraction did not finish in time.
+	ErrExtractionTimeout = errors.New("extraction did not finish in time")
+
+	// ErrNoExtractedData indicates an extraction result carries no data, e.g.
+	// the model or template found nothing to extract.
+	ErrNoExtractedData = errors.New("no extracted data")
+
 	// ErrUpstreamClient indicates a 4xx error from the target website.
 	ErrUpstreamClient = errors.New("upstream http client error")
 
@@ -50,6 +61,9 @@ var (
 	// ErrScrapeFailed indicates the scraping operation failed.
 	ErrScrapeFailed = errors.New("scrape failed")
 
+	// ErrScrapeTimeout indicates an asynchronous scrape did not finish in time.
+	ErrScrapeTimeout = errors.New("scrape did not finish in time")
+
 	// ErrProxyFailed indicates a proxy connection error.
 	ErrProxyFailed = errors.New("proxy error")
 
@@ -59,6 +73,10 @@ var (
 	// ErrScheduleFailed indicates a scheduled job error.
 	ErrScheduleFailed = errors.New("schedule error")
 
+	// ErrScheduleConfig indicates an invalid schedule request, such as a
+	// malformed cron expression.
+	ErrScheduleConfig = errors.New("invalid schedule config")
+
 	// ErrWebhookFailed indicates a webhook delivery error.
 	ErrWebhookFailed = errors.New("webhook error")
 
@@ -86,6 +104,14 @@ var (
 	// ErrCrawlerCancelled indicates Crawl.Wait() observed a CANCELLED terminal state.
 	ErrCrawlerCancelled = errors.New("crawler was cancelled")
 
+	// ErrDataNotRequested indicates result data the scrape was not configured
+	// to capture, e.g. SSL details without ScrapeConfig.SSL.
+	ErrDataNotRequested = errors.New("data not requested by the scrape config")
+
+	// ErrIFrameNotFound indicates no captured iframe matched, see
+	// ScrapeResult.IFrameByURL.
+	ErrIFrameNotFound = errors.New("iframe not found")
+
 	// ErrUnexpectedResponseFormat indicates the server returned a Content-Type the SDK didn't expect.
 	// Used for example when GET /crawl/{uuid}/urls returns JSON instead of streaming text.
 	ErrUnexpectedResponseFormat = errors.New("unexpected response format")
@@ -122,3 +148,47 @@ func (e *APIError) Error() string {
 	}
 	return base
 }
+
+// UpstreamStatusCode returns the HTTP status code of the target website for
+// an ErrUpstreamClient or ErrUpstreamServer error, or 0 for any other error.
+//
+// Example:
+//
+//	_, err := client.Scrape(config)
+//	switch {
+//	case scrapfly.IsUpstreamNotFound(err):
+//	    // page is gone, skip it
+//	case scrapfly.IsUpstreamForbidden(err), scrapfly.IsUpstreamRateLimited(err):
+//	    // retry later, or with ASP and residential proxies
+//	case scrapfly.UpstreamStatusCode(err) >= 500:
+//	    // target is down, retry
+//	}
+func UpstreamStatusCode(err error) int {
+	if !errors.Is(err, ErrUpstreamClient) && !errors.Is(err, ErrUpstreamServer) {
+		return 0
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return 0
+	}
+	return apiErr.HTTPStatusCode
+}
+
+// IsUpstreamNotFound reports whether err is the target website answering
+// 404 Not Found or 410 Gone.
+func IsUpstreamNotFound(err error) bool {
+	code := UpstreamStatusCode(err)
+	return code == http.StatusNotFound || code == http.StatusGone
+}
+
+// IsUpstreamForbidden reports whether err is the target website answering
+// 403 Forbidden, often a block.
+func IsUpstreamForbidden(err error) bool {
+	return UpstreamStatusCode(err) == http.StatusForbidden
+}
+
+// IsUpstreamRateLimited reports whether err is the target website answering
+// 429 Too Many Requests.
+func IsUpstreamRateLimited(err error) bool {
+	return UpstreamStatusCode(err) == http.StatusTooManyRequests
+}
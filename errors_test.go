@@ -0,0 +1,43 @@
+package scrapfly
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestUpstreamStatusPredicates(t *testing.T) {
+	for _, tc := range []struct {
+		status                           int
+		notFound, forbidden, rateLimited bool
+	}{
+		{status: http.StatusNotFound, notFound: true},
+		{status: http.StatusGone, notFound: true},
+		{status: http.StatusForbidden, forbidden: true},
+		{status: http.StatusTooManyRequests, rateLimited: true},
+		{status: http.StatusBadGateway},
+	} {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"result": {"success": false, "status": "DONE", "status_code": %d, "error": {"code": "ERR::SCRAPE::BAD_UPSTREAM_RESPONSE", "message": "upstream error", "retryable": false}}}`, tc.status)
+		})
+		_, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"})
+		if got := UpstreamStatusCode(err); got != tc.status {
+			t.Errorf("%d: UpstreamStatusCode = %d", tc.status, got)
+		}
+		if IsUpstreamNotFound(err) != tc.notFound || IsUpstreamForbidden(err) != tc.forbidden || IsUpstreamRateLimited(err) != tc.rateLimited {
+			t.Errorf("%d: notFound=%t forbidden=%t rateLimited=%t", tc.status,
+				IsUpstreamNotFound(err), IsUpstreamForbidden(err), IsUpstreamRateLimited(err))
+		}
+	}
+
+	// API errors carry the API status, not an upstream one.
+	apiErr := fmt.Errorf("%w: %w", ErrAPIClient, &APIError{HTTPStatusCode: http.StatusNotFound})
+	if UpstreamStatusCode(apiErr) != 0 || IsUpstreamNotFound(apiErr) {
+		t.Errorf("API 404 reported as upstream: %v", apiErr)
+	}
+	if UpstreamStatusCode(nil) != 0 || UpstreamStatusCode(errors.New("boom")) != 0 {
+		t.Error("expected 0 for non upstream errors")
+	}
+}
@@ -9,10 +9,13 @@ require (
 	github.com/vmihailenco/msgpack/v5 v5.4.1
 )
 
-require github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+require (
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	golang.org/x/text v0.30.0 // indirect
+)
 
 require (
 	github.com/andybalholm/cascadia v1.3.3 // indirect
 	github.com/google/jsonschema-go v0.3.0
-	golang.org/x/net v0.46.0 // indirect
+	golang.org/x/net v0.46.0
 )
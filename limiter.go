@@ -0,0 +1,240 @@
+package scrapfly
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// concurrencyLimiter bounds the number of in-flight API calls issued by a
+// Client, across every goroutine and every method that shares the Client.
+type concurrencyLimiter struct {
+	mu    sync.Mutex
+	slots chan struct{}
+	// resolving is closed when the account lookup sizing slots ends.
+	resolving chan struct{}
+}
+
+// withoutConcurrencySlotKey marks a request context as exempt from the
+// global concurrency limit, for the account lookup sizing it.
+type withoutConcurrencySlotKey struct{}
+
+// SetGlobalConcurrency caps the number of API requests the client has in
+// flight at the same time, across all goroutines, methods and
+// ConcurrentScrape calls sharing this client. Every request goes through the
+// limit, including retries, account, crawler and monitoring calls, async
+// polling and large object or screenshot downloads. A slot is held until the
+// response headers are received. Requests over the limit block until a slot
+// frees up.
+//
+//   - n > 0: at most n requests are in flight
+//   - n == 0: the limit is discovered from the account concurrency limit on
+//     the first request (see Client.Account)
+//   - n < 0: no client-side limit
+//
+// The limit is opt-in: a new client has none, as discovering it would add an
+// account call to every client. Call SetGlobalConcurrency(0) to size it from
+// the account. Configure it before the client is shared between goroutines.
+//
+// Example:
+//
+//	client, _ := scrapfly.New("YOUR_API_KEY")
+//	client.SetGlobalConcurrency(0) // use the account concurrency limit
+func (c *Client) SetGlobalConcurrency(n int) {
+	if n < 0 {
+		c.limiter = nil
+		return
+	}
+	l := &concurrencyLimiter{}
+	if n > 0 {
+		l.slots = make(chan struct{}, n)
+	}
+	c.limiter = l
+}
+
+// acquireSlot blocks until a global concurrency slot is available and returns
+// the function releasing it. It is a no-op when no limit is configured.
+func (c *Client) acquireSlot(ctx context.Context) (func(), error) {
+	l := c.limiter
+	if l == nil || ctx.Value(withoutConcurrencySlotKey{}) != nil {
+		return func() {}, nil
+	}
+	slots, err := l.channel(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// channel returns the semaphore channel, sizing it from the account
+// concurrency limit on first use when no explicit limit was given. A single
+// goroutine looks the account up, outside of the lock; the others wait for
+// it and try again if it failed.
+func (l *concurrencyLimiter) channel(ctx context.Context, c *Client) (chan struct{}, error) {
+	for {
+		l.mu.Lock()
+		if l.slots != nil {
+			l.mu.Unlock()
+			return l.slots, nil
+		}
+		if resolving := l.resolving; resolving != nil {
+			l.mu.Unlock()
+			select {
+			case <-resolving:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		resolving := make(chan struct{})
+		l.resolving = resolving
+		l.mu.Unlock()
+
+		n, err := accountConcurrency(ctx, c)
+		l.mu.Lock()
+		if err == nil {
+			l.slots = make(chan struct{}, n)
+		}
+		l.resolving = nil
+		close(resolving)
+		l.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// accountConcurrency returns the account concurrency limit, looked up
+// without taking a slot of the limit it sizes.
+func accountConcurrency(ctx context.Context, c *Client) (int, error) {
+	account, err := c.account(context.WithValue(ctx, withoutConcurrencySlotKey{}, true))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get account for concurrency limit: %w", err)
+	}
+	n := account.Subscription.Usage.Scrape.ConcurrentLimit
+	if n <= 0 {
+		n = account.Subscription.MaxConcurrency
+	}
+	if n <= 0 {
+		n = 1
+	}
+	DefaultLogger.Info("global concurrency not provided - setting it to", n, "from account info")
+	return n, nil
+}
+
+// DomainThrottle configures client-side throttling of requests per target
+// root domain (e.g. "example.co.uk" for "shop.example.co.uk").
+//
+// Requests to the same root domain are spaced by 1/RequestsPerSecond, plus a
+// random delay in [0, Jitter) so the traffic doesn't follow a fixed beat.
+// Requests to different domains don't wait on each other.
+type DomainThrottle struct {
+	// RequestsPerSecond is the maximum request rate per root domain.
+	// Zero or negative means domains are not throttled unless listed in Domains.
+	RequestsPerSecond float64
+	// Jitter is the upper bound of the random delay added between two requests.
+	Jitter time.Duration
+	// Domains overrides RequestsPerSecond for specific root domains.
+	Domains map[string]float64
+}
+
+// domainThrottler tracks the next allowed dispatch time for each root domain.
+type domainThrottler struct {
+	config DomainThrottle
+	mu     sync.Mutex
+	next   map[string]time.Time
+}
+
+// SetDomainThrottle enables per root domain throttling for Scrape,
+// ScrapeProxified and Screenshot calls. Calls wait for their turn before
+// being sent to the API. Passing nil disables throttling (default).
+//
+// This is independent of SetGlobalConcurrency: the throttle spaces requests
+// sent to the same target, the global limit bounds requests in flight.
+//
+// Example:
+//
+//	client.SetDomainThrottle(&scrapfly.DomainThrottle{
+//	    RequestsPerSecond: 2,
+//	    Jitter:            250 * time.Millisecond,
+//	    Domains:           map[string]float64{"example.com": 0.5},
+//	})
+func (c *Client) SetDomainThrottle(throttle *DomainThrottle) {
+	if throttle == nil {
+		c.throttler = nil
+		return
+	}
+	c.throttler = &domainThrottler{
+		config: *throttle,
+		next:   make(map[string]time.Time),
+	}
+}
+
+// waitForDomain blocks until a request to targetURL may be dispatched.
+// It is a no-op when no throttle is configured.
+func (c *Client) waitForDomain(ctx context.Context, targetURL string) error {
+	t := c.throttler
+	if t == nil {
+		return nil
+	}
+	delay := t.reserve(rootDomain(targetURL), time.Now())
+	if delay <= 0 {
+		return nil
+	}
+	return sleepContext(ctx, delay)
+}
+
+// reserve books the next dispatch slot for domain and returns how long the
+// caller has to wait for it.
+func (t *domainThrottler) reserve(domain string, now time.Time) time.Duration {
+	rps := t.config.RequestsPerSecond
+	if override, ok := t.config.Domains[domain]; ok {
+		rps = override
+	}
+	if rps <= 0 || domain == "" {
+		return 0
+	}
+	interval := time.Duration(float64(time.Second) / rps)
+	if t.config.Jitter > 0 {
+		interval += time.Duration(rand.Int64N(int64(t.config.Jitter)))
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	slot := t.next[domain]
+	if slot.Before(now) {
+		slot = now
+	}
+	t.next[domain] = slot.Add(interval)
+	return slot.Sub(now)
+}
+
+// rootDomain returns the registrable domain of rawURL ("example.co.uk" for
+// "https://shop.example.co.uk/a"), or the bare host when it has none
+// (IP addresses, localhost).
+func rootDomain(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+	if net.ParseIP(host) != nil {
+		return host
+	}
+	if domain, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		return domain
+	}
+	return host
+}
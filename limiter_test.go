@@ -0,0 +1,177 @@
+package scrapfly
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const scrapeDoneResponse = `{"result": {"success": true, "status": "DONE", "status_code": 200, "content": "ok", "content_type": "text/html"}}`
+
+func TestClient_GlobalConcurrencyBoundsInFlightCalls(t *testing.T) {
+	var inFlight, maxInFlight int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+	client.SetGlobalConcurrency(2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&maxInFlight); got > 2 {
+		t.Errorf("max in-flight = %d, want <= 2", got)
+	}
+}
+
+func TestClient_GlobalConcurrencyAutoDiscovery(t *testing.T) {
+	var accountCalls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/account" {
+			atomic.AddInt32(&accountCalls, 1)
+			_, _ = w.Write([]byte(`{"subscription": {"usage": {"scrape": {"concurrent_limit": 3}}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+	client.SetGlobalConcurrency(0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if accountCalls := atomic.LoadInt32(&accountCalls); accountCalls != 1 {
+		t.Errorf("account fetched %d times, want 1", accountCalls)
+	}
+	if got := cap(client.limiter.slots); got != 3 {
+		t.Errorf("limit = %d, want 3", got)
+	}
+}
+
+func TestClient_GlobalConcurrencyCoversEveryRequest(t *testing.T) {
+	var inFlight, maxInFlight int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/account" {
+			_, _ = w.Write([]byte(`{"subscription": {"usage": {"scrape": {"concurrent_limit": 1}}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+	client.SetGlobalConcurrency(1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Account(); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&maxInFlight); got > 1 {
+		t.Errorf("max in-flight = %d, want <= 1", got)
+	}
+}
+
+func TestRootDomain(t *testing.T) {
+	cases := map[string]string{
+		"https://shop.example.co.uk/a?b=c": "example.co.uk",
+		"https://www.Example.com":          "example.com",
+		"http://127.0.0.1:8080/":           "127.0.0.1",
+		"http://localhost/":                "localhost",
+	}
+	for in, want := range cases {
+		if got := rootDomain(in); got != want {
+			t.Errorf("rootDomain(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDomainThrottler_SpacesRequestsPerDomain(t *testing.T) {
+	throttler := &domainThrottler{
+		config: DomainThrottle{
+			RequestsPerSecond: 2,
+			Domains:           map[string]float64{"slow.com": 0.5, "free.com": 0},
+		},
+		next: make(map[string]time.Time),
+	}
+	now := time.Now()
+
+	if d := throttler.reserve("example.com", now); d != 0 {
+		t.Errorf("first request should not wait, got %v", d)
+	}
+	if d := throttler.reserve("example.com", now); d != 500*time.Millisecond {
+		t.Errorf("second request should wait 500ms, got %v", d)
+	}
+	if d := throttler.reserve("other.com", now); d != 0 {
+		t.Errorf("other domain should not wait, got %v", d)
+	}
+	throttler.reserve("slow.com", now)
+	if d := throttler.reserve("slow.com", now); d != 2*time.Second {
+		t.Errorf("override should wait 2s, got %v", d)
+	}
+	throttler.reserve("free.com", now)
+	if d := throttler.reserve("free.com", now); d != 0 {
+		t.Errorf("disabled domain should not wait, got %v", d)
+	}
+}
+
+func TestDomainThrottler_Jitter(t *testing.T) {
+	throttler := &domainThrottler{
+		config: DomainThrottle{RequestsPerSecond: 10, Jitter: 50 * time.Millisecond},
+		next:   make(map[string]time.Time),
+	}
+	now := time.Now()
+	throttler.reserve("example.com", now)
+	d := throttler.reserve("example.com", now)
+	if d < 100*time.Millisecond || d >= 150*time.Millisecond {
+		t.Errorf("wait = %v, want in [100ms, 150ms)", d)
+	}
+}
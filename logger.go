@@ -1,8 +1,12 @@
 package scrapfly
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"log/slog"
 	"os"
+	"strings"
 )
 
 // LogLevel defines the severity level for log messages.
@@ -24,6 +28,7 @@ const (
 type Logger struct {
 	logger *log.Logger
 	level  LogLevel
+	slog   *slog.Logger
 }
 
 // NewLogger creates a new Logger instance with the given name prefix.
@@ -40,6 +45,20 @@ func NewLogger(name string) *Logger {
 	}
 }
 
+// SetSlog forwards all messages to a structured slog.Logger instead of the
+// standard logger. Messages that carry details, such as request retries,
+// are emitted with them as slog attributes (attempt, max_attempts, status,
+// error, backoff, elapsed...). The minimum level set with SetLevel still
+// applies. Passing nil restores the standard logger.
+//
+// Example:
+//
+//	scrapfly.DefaultLogger.SetSlog(slog.Default())
+//	scrapfly.DefaultLogger.SetLevel(scrapfly.LevelDebug)
+func (l *Logger) SetSlog(logger *slog.Logger) {
+	l.slog = logger
+}
+
 // SetLevel sets the minimum logging level.
 // Only messages at this level or higher will be logged.
 func (l *Logger) SetLevel(level LogLevel) {
@@ -49,33 +68,70 @@ func (l *Logger) SetLevel(level LogLevel) {
 // Debug logs a debug-level message.
 // These messages are only logged when the level is set to LevelDebug.
 func (l *Logger) Debug(v ...interface{}) {
-	if l.level <= LevelDebug {
-		l.logger.Println(append([]interface{}{"[DEBUG]"}, v...)...)
-	}
+	l.log(LevelDebug, v...)
 }
 
 // Info logs an informational message.
 // These messages are logged when the level is LevelInfo or lower.
 func (l *Logger) Info(v ...interface{}) {
-	if l.level <= LevelInfo {
-		l.logger.Println(append([]interface{}{"[INFO]"}, v...)...)
-	}
+	l.log(LevelInfo, v...)
 }
 
 // Warn logs a warning message.
 // These messages are logged when the level is LevelWarn or lower.
 func (l *Logger) Warn(v ...interface{}) {
-	if l.level <= LevelWarn {
-		l.logger.Println(append([]interface{}{"[WARN]"}, v...)...)
-	}
+	l.log(LevelWarn, v...)
 }
 
 // Error logs an error message.
 // These messages are always logged regardless of the level setting.
 func (l *Logger) Error(v ...interface{}) {
-	if l.level <= LevelError {
-		l.logger.Println(append([]interface{}{"[ERROR]"}, v...)...)
+	l.log(LevelError, v...)
+}
+
+// slogLevels maps LogLevel to slog levels.
+var slogLevels = map[LogLevel]slog.Level{
+	LevelDebug: slog.LevelDebug,
+	LevelInfo:  slog.LevelInfo,
+	LevelWarn:  slog.LevelWarn,
+	LevelError: slog.LevelError,
+}
+
+// logTags are the message prefixes of the standard logger output.
+var logTags = map[LogLevel]string{
+	LevelDebug: "[DEBUG]",
+	LevelInfo:  "[INFO]",
+	LevelWarn:  "[WARN]",
+	LevelError: "[ERROR]",
+}
+
+func (l *Logger) log(level LogLevel, v ...interface{}) {
+	if l.level > level {
+		return
+	}
+	if l.slog != nil {
+		l.slog.Log(context.Background(), slogLevels[level], strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+		return
+	}
+	l.logger.Println(append([]interface{}{logTags[level]}, v...)...)
+}
+
+// debugFields logs a debug-level message with key/value details, as slog
+// attributes when SetSlog is used, or appended as key=value otherwise.
+func (l *Logger) debugFields(msg string, keysAndValues ...interface{}) {
+	if l.level > LevelDebug {
+		return
+	}
+	if l.slog != nil {
+		l.slog.Debug(msg, keysAndValues...)
+		return
+	}
+	var b strings.Builder
+	b.WriteString(msg)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		fmt.Fprintf(&b, " %v=%v", keysAndValues[i], keysAndValues[i+1])
 	}
+	l.logger.Println(logTags[LevelDebug], b.String())
 }
 
 // DefaultLogger is the default logger used by the Scrapfly SDK.
@@ -0,0 +1,93 @@
+package scrapfly
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// captureDefaultLogger redirects DefaultLogger at debug level for the test.
+func captureDefaultLogger(t *testing.T) *Logger {
+	t.Helper()
+	previous := DefaultLogger
+	DefaultLogger = NewLogger("scrapfly")
+	DefaultLogger.SetLevel(LevelDebug)
+	t.Cleanup(func() { DefaultLogger = previous })
+	return DefaultLogger
+}
+
+func TestLogger_SlogRetryFields(t *testing.T) {
+	var out bytes.Buffer
+	captureDefaultLogger(t).SetSlog(slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+	if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var retry map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid slog record %q: %v", line, err)
+		}
+		if record["msg"] == "request failed, retrying" {
+			retry = record
+		}
+	}
+	if retry == nil {
+		t.Fatalf("no retry record in:\n%s", out.String())
+	}
+	if retry["level"] != "DEBUG" || retry["attempt"] != float64(1) || retry["max_attempts"] != float64(defaultRetries) || retry["status"] != float64(http.StatusBadGateway) {
+		t.Errorf("retry record = %v", retry)
+	}
+	if retry["path"] != "/scrape" || retry["backoff"] == nil || retry["elapsed"] == nil {
+		t.Errorf("retry record = %v", retry)
+	}
+}
+
+func TestLogger_RetryFieldsPlainLogger(t *testing.T) {
+	var out bytes.Buffer
+	logger := captureDefaultLogger(t)
+	logger.logger = log.New(&out, "", 0)
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	if _, err := client.Screenshot(&ScreenshotConfig{URL: "https://example.com"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(out.String(), "[DEBUG] request failed, retrying method=GET path=/screenshot attempt=1 max_attempts=3 status=503 backoff=1s") {
+		t.Errorf("unexpected log output:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "request failed, giving up method=GET path=/screenshot attempt=3 max_attempts=3 status=503 elapsed=") {
+		t.Errorf("missing final attempt log:\n%s", out.String())
+	}
+}
+
+func TestLogger_SlogLevels(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewLogger("test")
+	logger.SetSlog(slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	logger.Debug("hidden at info level")
+	logger.Warn("disk", "almost", "full")
+	if strings.Contains(out.String(), "hidden") {
+		t.Error("SetLevel must still filter messages")
+	}
+	if !strings.Contains(out.String(), `level=WARN msg="disk almost full"`) {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
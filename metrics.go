@@ -0,0 +1,80 @@
+package scrapfly
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Metrics receives measurements from the Client request path, e.g. to export
+// them with prometheus/client_golang without the SDK depending on it.
+// Implementations must be safe for concurrent use. Embed NoopMetrics to only
+// implement some of the methods.
+type Metrics interface {
+	// ObserveRequest is called once per HTTP attempt, retries included, with
+	// the API endpoint (see below), the response status code (0 when the
+	// request failed without a response) and the time to response headers.
+	//
+	// The endpoint is the first segment of the request path, e.g. "/scrape",
+	// "/screenshot" or "/crawl", so it can be used as a low-cardinality label.
+	ObserveRequest(endpoint string, status int, latency time.Duration)
+	// ObserveRetry is called each time the SDK retries a request to endpoint.
+	ObserveRetry(endpoint string)
+	// ObserveCost is called with the API credits billed for a scrape,
+	// screenshot or extraction, when the API reports it.
+	ObserveCost(credits int)
+}
+
+// NoopMetrics is a Metrics implementation that discards everything.
+// It is the default.
+type NoopMetrics struct{}
+
+// ObserveRequest implements Metrics.
+func (NoopMetrics) ObserveRequest(string, int, time.Duration) {}
+
+// ObserveRetry implements Metrics.
+func (NoopMetrics) ObserveRetry(string) {}
+
+// ObserveCost implements Metrics.
+func (NoopMetrics) ObserveCost(int) {}
+
+// SetMetrics installs the Metrics the client reports requests, retries and
+// costs to. Passing nil restores NoopMetrics.
+//
+// Example:
+//
+//	type promMetrics struct {
+//	    scrapfly.NoopMetrics
+//	    latency *prometheus.HistogramVec
+//	}
+//
+//	func (m promMetrics) ObserveRequest(endpoint string, status int, latency time.Duration) {
+//	    m.latency.WithLabelValues(endpoint, strconv.Itoa(status)).Observe(latency.Seconds())
+//	}
+//
+//	client.SetMetrics(promMetrics{latency: latencyHistogram})
+func (c *Client) SetMetrics(metrics Metrics) {
+	c.metrics = metrics
+}
+
+// metricsSink returns the installed Metrics, or NoopMetrics.
+func (c *Client) metricsSink() Metrics {
+	if c.metrics == nil {
+		return NoopMetrics{}
+	}
+	return c.metrics
+}
+
+// metricsEndpoint returns the endpoint label for a request URL, ignoring
+// the SetPathPrefix path prefix.
+func metricsEndpoint(u *url.URL, pathPrefix string) string {
+	path := u.Path
+	if pathPrefix != "" && (path == pathPrefix || strings.HasPrefix(path, pathPrefix+"/")) {
+		path = path[len(pathPrefix):]
+	}
+	path = strings.TrimPrefix(path, "/")
+	if i := strings.Index(path, "/"); i >= 0 {
+		path = path[:i]
+	}
+	return "/" + path
+}
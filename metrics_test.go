@@ -0,0 +1,113 @@
+package scrapfly
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type observedRequest struct {
+	endpoint string
+	status   int
+}
+
+type recordingMetrics struct {
+	mu       sync.Mutex
+	requests []observedRequest
+	retries  []string
+	costs    []int
+}
+
+func (m *recordingMetrics) ObserveRequest(endpoint string, status int, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, observedRequest{endpoint, status})
+}
+
+func (m *recordingMetrics) ObserveRetry(endpoint string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries = append(m.retries, endpoint)
+}
+
+func (m *recordingMetrics) ObserveCost(credits int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.costs = append(m.costs, credits)
+}
+
+func TestClient_MetricsObserveRequestsRetriesAndCost(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"result": {"success": true, "status": "DONE", "status_code": 200, "content": "ok"}, "context": {"cost": {"total": 25}}}`))
+	})
+	metrics := &recordingMetrics{}
+	client.SetMetrics(metrics)
+
+	if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	want := []observedRequest{{"/scrape", http.StatusServiceUnavailable}, {"/scrape", http.StatusOK}}
+	if len(metrics.requests) != 2 || metrics.requests[0] != want[0] || metrics.requests[1] != want[1] {
+		t.Errorf("requests = %v, want %v", metrics.requests, want)
+	}
+	if len(metrics.retries) != 1 || metrics.retries[0] != "/scrape" {
+		t.Errorf("retries = %v", metrics.retries)
+	}
+	if len(metrics.costs) != 1 || metrics.costs[0] != 25 {
+		t.Errorf("costs = %v", metrics.costs)
+	}
+}
+
+func TestClient_MetricsCostFromHeader(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("X-Scrapfly-Api-Cost", "60")
+		_, _ = w.Write([]byte("png"))
+	})
+	metrics := &recordingMetrics{}
+	client.SetMetrics(metrics)
+
+	if _, err := client.Screenshot(&ScreenshotConfig{URL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(metrics.requests) != 1 || metrics.requests[0].endpoint != "/screenshot" {
+		t.Errorf("requests = %v", metrics.requests)
+	}
+	if len(metrics.costs) != 1 || metrics.costs[0] != 60 {
+		t.Errorf("costs = %v", metrics.costs)
+	}
+}
+
+func TestClient_SetMetricsNil(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+	client.SetMetrics(nil)
+	if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	for raw, want := range map[string]string{
+		"https://api.scrapfly.io/scrape?key=x":         "/scrape",
+		"https://api.scrapfly.io/crawl/abc-123/status": "/crawl",
+		"https://api.scrapfly.io/":                     "/",
+		"https://api.scrapfly.io/extraction?x=1":       "/extraction",
+	} {
+		u, _ := url.Parse(raw)
+		if got := metricsEndpoint(u, ""); got != want {
+			t.Errorf("metricsEndpoint(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
@@ -84,9 +84,9 @@ const monitoringDatetimeFormat = "2006-01-02 15:04:05"
 // metrics call, scoped to the given product path (e.g. "/scrape",
 // "/screenshot", "/extraction", "/crawl").
 func (c *Client) buildMonitoringMetricsURL(productPath string, opts MonitoringMetricsOptions) string {
-	endpointURL, _ := url.Parse(c.host + productPath + "/monitoring/metrics")
+	endpointURL, _ := url.Parse(c.endpoint(productPath + "/monitoring/metrics"))
 	params := url.Values{}
-	params.Set("key", c.key)
+	params.Set("key", c.APIKey())
 	format := opts.Format
 	if format == "" {
 		format = MonitoringDataFormatStructured
@@ -118,9 +118,9 @@ func (c *Client) buildMonitoringTargetURL(productPath string, opts MonitoringTar
 	if (!opts.Start.IsZero()) != (!opts.End.IsZero()) {
 		return "", fmt.Errorf("monitoring target metrics: start and end must be provided together")
 	}
-	endpointURL, _ := url.Parse(c.host + productPath + "/monitoring/metrics/target")
+	endpointURL, _ := url.Parse(c.endpoint(productPath + "/monitoring/metrics/target"))
 	params := url.Values{}
-	params.Set("key", c.key)
+	params.Set("key", c.APIKey())
 	params.Set("domain", opts.Domain)
 	params.Set("group_subdomain", strconv.FormatBool(opts.GroupSubdomain))
 	if !opts.Start.IsZero() && !opts.End.IsZero() {
@@ -153,6 +153,94 @@ func (c *Client) GetMonitoringTargetMetrics(opts MonitoringTargetMetricsOptions)
 	return c.doMonitoringRequest(requestURL)
 }
 
+// ScrapeHistoryFilter configures a ListScrapes call. All fields are
+// optional — an empty filter returns the first page of the most recent
+// scrapes. Start/End must be set together.
+type ScrapeHistoryFilter struct {
+	// Tags only returns scrapes carrying all of these tags (see ScrapeConfig.Tags).
+	Tags []string
+	// Status only returns scrapes in this status (e.g. "DONE", "FAILED").
+	Status string
+	Start  time.Time
+	End    time.Time
+	// Page is the 1-based page number. Defaults to the first page.
+	Page int
+	// PerPage is the number of scrapes per page. Defaults to the server default.
+	PerPage int
+}
+
+// ScrapeSummary is a single scrape log entry returned by ListScrapes.
+type ScrapeSummary struct {
+	UUID          string   `json:"uuid"`
+	URL           string   `json:"url"`
+	Status        string   `json:"status"`
+	StatusCode    int      `json:"status_code"`
+	Success       bool     `json:"success"`
+	Tags          []string `json:"tags"`
+	CorrelationID *string  `json:"correlation_id"`
+	Cost          int      `json:"cost"`
+	Project       string   `json:"project"`
+	Env           string   `json:"env"`
+	LogURL        string   `json:"log_url"`
+	CreatedAt     string   `json:"created_at"`
+}
+
+// ListScrapes retrieves past scrapes from the monitoring logs, e.g. to
+// audit every scrape tagged by a given job.
+//
+// Example:
+//
+//	scrapes, err := client.ListScrapes(scrapfly.ScrapeHistoryFilter{
+//	    Tags:   []string{"daily-prices"},
+//	    Status: "FAILED",
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, s := range scrapes {
+//	    fmt.Println(s.URL, s.LogURL)
+//	}
+func (c *Client) ListScrapes(filter ScrapeHistoryFilter) ([]ScrapeSummary, error) {
+	if (!filter.Start.IsZero()) != (!filter.End.IsZero()) {
+		return nil, fmt.Errorf("list scrapes: start and end must be provided together")
+	}
+	endpointURL, _ := url.Parse(c.endpoint("/scrape/monitoring/logs"))
+	params := url.Values{}
+	params.Set("key", c.APIKey())
+	if len(filter.Tags) > 0 {
+		params.Set("tags", strings.Join(filter.Tags, ","))
+	}
+	if filter.Status != "" {
+		params.Set("status", filter.Status)
+	}
+	if !filter.Start.IsZero() && !filter.End.IsZero() {
+		params.Set("start", filter.Start.UTC().Format(monitoringDatetimeFormat))
+		params.Set("end", filter.End.UTC().Format(monitoringDatetimeFormat))
+	}
+	if filter.Page > 0 {
+		params.Set("page", strconv.Itoa(filter.Page))
+	}
+	if filter.PerPage > 0 {
+		params.Set("per_page", strconv.Itoa(filter.PerPage))
+	}
+	endpointURL.RawQuery = params.Encode()
+
+	data, err := c.doMonitoringRequest(endpointURL.String())
+	if err != nil {
+		return nil, err
+	}
+	var page struct {
+		Data []ScrapeSummary `json:"data"`
+	}
+	if err := remarshal(data, &page); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scrape logs: %w", err)
+	}
+	if page.Data == nil {
+		page.Data = []ScrapeSummary{}
+	}
+	return page.Data, nil
+}
+
 // ── Screenshot API ───────────────────────────────────────────────────
 
 func (c *Client) GetScreenshotMonitoringMetrics(opts MonitoringMetricsOptions) (map[string]any, error) {
@@ -217,9 +305,9 @@ func (c *Client) buildBrowserMonitoringURL(path string, opts CloudBrowserMonitor
 	if (!opts.Start.IsZero()) != (!opts.End.IsZero()) {
 		return "", fmt.Errorf("cloud browser monitoring: start and end must be provided together")
 	}
-	endpointURL, _ := url.Parse(c.host + path)
+	endpointURL, _ := url.Parse(c.endpoint(path))
 	params := url.Values{}
-	params.Set("key", c.key)
+	params.Set("key", c.APIKey())
 	if !opts.Start.IsZero() && !opts.End.IsZero() {
 		params.Set("start", opts.Start.UTC().Format(monitoringDatetimeFormat))
 		params.Set("end", opts.End.UTC().Format(monitoringDatetimeFormat))
@@ -238,10 +326,10 @@ func (c *Client) doMonitoringRequest(requestURL string) (map[string]any, error)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,52 @@
+package scrapfly
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClient_ListScrapesFilters(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/scrape/monitoring/logs" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("tags") != "job-1,prices" {
+			t.Errorf("tags = %q", q.Get("tags"))
+		}
+		if q.Get("status") != "FAILED" {
+			t.Errorf("status = %q", q.Get("status"))
+		}
+		if q.Get("start") != "2024-01-01 00:00:00" || q.Get("end") != "2024-01-02 00:00:00" {
+			t.Errorf("start/end = %q/%q", q.Get("start"), q.Get("end"))
+		}
+		if q.Get("page") != "2" || q.Get("per_page") != "50" {
+			t.Errorf("page/per_page = %q/%q", q.Get("page"), q.Get("per_page"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": [{"uuid": "u1", "url": "https://example.com", "status": "FAILED", "status_code": 403, "tags": ["job-1", "prices"], "cost": 1}]}`))
+	})
+
+	scrapes, err := client.ListScrapes(ScrapeHistoryFilter{
+		Tags:    []string{"job-1", "prices"},
+		Status:  "FAILED",
+		Start:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:     time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Page:    2,
+		PerPage: 50,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scrapes) != 1 || scrapes[0].UUID != "u1" || scrapes[0].StatusCode != 403 || len(scrapes[0].Tags) != 2 {
+		t.Errorf("scrapes = %+v", scrapes)
+	}
+}
+
+func TestClient_ListScrapesRequiresStartAndEnd(t *testing.T) {
+	client, _ := New("__API_KEY__")
+	if _, err := client.ListScrapes(ScrapeHistoryFilter{Start: time.Now()}); err == nil {
+		t.Error("expected error when only start is set")
+	}
+}
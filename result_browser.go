@@ -0,0 +1,523 @@
+package scrapfly
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// JSScenarioResult is the execution report of a JS scenario, returned in
+// BrowserData.JSScenario when ScrapeConfig.JSScenario is set.
+type JSScenarioResult struct {
+	// Duration is the total scenario execution time in seconds.
+	Duration float64 `json:"duration"`
+	// Executed is the number of steps that were executed.
+	Executed int `json:"executed"`
+	// Response is the navigation response captured by the scenario, if any.
+	// See NavigationResponse for a typed access.
+	Response interface{} `json:"response"`
+	// Steps holds the report of every step, in scenario order.
+	Steps []JSScenarioStepResult `json:"steps"`
+}
+
+// JSScenarioStepResult is the execution report of a single scenario step.
+type JSScenarioStepResult struct {
+	// Action is the step action name (click, fill, wait, ...).
+	Action string `json:"action"`
+	// Config is the step configuration as interpreted by the browser,
+	// e.g. an object for click or a number of milliseconds for wait.
+	Config interface{} `json:"config"`
+	// Duration is the step execution time in seconds.
+	Duration float64 `json:"duration"`
+	// Executed reports whether the step ran (conditions can skip steps).
+	Executed bool `json:"executed"`
+	// Result is the value returned by the step (e.g. an execute script result).
+	Result interface{} `json:"result"`
+	// Success reports whether the step completed without error.
+	Success bool `json:"success"`
+	// Response is the response of the navigation the step triggered (a
+	// click submitting a form, wait_for_navigation...), nil when the step
+	// did not navigate or the response was not captured. See
+	// NavigationResponse for a typed access.
+	Response interface{} `json:"response"`
+}
+
+// NavigationResponse decodes the response of the navigation the step
+// triggered. It returns nil and no error when there is none.
+func (s *JSScenarioStepResult) NavigationResponse() (*ScenarioResponse, error) {
+	return decodeScenarioResponse(s.Response)
+}
+
+// ScenarioResponse is a navigation response captured during a JS scenario.
+type ScenarioResponse struct {
+	// URL is the URL of the response, after redirects.
+	URL string `json:"url"`
+	// Status is the HTTP status code.
+	Status int `json:"status"`
+	// Headers are the response headers.
+	Headers map[string]string `json:"headers"`
+	// Body is the response body.
+	Body string `json:"body"`
+	// ContentType is the response content type.
+	ContentType string `json:"content_type"`
+	// Format is the encoding of Body ("text" or "binary").
+	Format string `json:"format"`
+}
+
+// NavigationResponse returns the last navigation response captured by the
+// scenario: the scenario level Response when set, else the response of the
+// last step that captured one. It returns nil and no error when there is
+// none.
+//
+// Example:
+//
+//	report, _ := result.Result.BrowserData.ScenarioResult()
+//	response, err := report.NavigationResponse()
+//	if err == nil && response != nil {
+//	    fmt.Println(response.Status, response.URL)
+//	}
+func (r *JSScenarioResult) NavigationResponse() (*ScenarioResponse, error) {
+	if r.Response != nil {
+		return decodeScenarioResponse(r.Response)
+	}
+	for i := len(r.Steps) - 1; i >= 0; i-- {
+		if r.Steps[i].Response != nil {
+			return r.Steps[i].NavigationResponse()
+		}
+	}
+	return nil, nil
+}
+
+// decodeScenarioResponse decodes a raw scenario navigation response. The
+// shape is only converted on access, so an unexpected payload doesn't make
+// the whole scenario report undecodable.
+func decodeScenarioResponse(raw interface{}) (*ScenarioResponse, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	var response ScenarioResponse
+	if err := remarshal(raw, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode js_scenario response: %w", err)
+	}
+	return &response, nil
+}
+
+// ScenarioResult decodes the JS scenario execution report.
+// Returns nil and no error when no scenario was executed.
+//
+// Example:
+//
+//	report, err := result.Result.BrowserData.ScenarioResult()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, step := range report.Steps {
+//	    fmt.Println(step.Action, step.Success)
+//	}
+func (b *BrowserData) ScenarioResult() (*JSScenarioResult, error) {
+	if b.JSScenario == nil {
+		return nil, nil
+	}
+	var result JSScenarioResult
+	if err := remarshal(b.JSScenario, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode js_scenario result: %w", err)
+	}
+	return &result, nil
+}
+
+// XHRCall is an XHR / fetch request captured by the browser during rendering.
+type XHRCall struct {
+	// URL is the requested URL.
+	URL string `json:"url"`
+	// Method is the HTTP method of the request.
+	Method string `json:"method"`
+	// Type is the kind of request issued by the page (xhr, fetch).
+	Type string `json:"type"`
+	// Headers are the request headers sent by the browser.
+	Headers map[string]string `json:"headers"`
+	// Body is the request body, nil for bodiless requests.
+	Body *string `json:"body"`
+	// Response is the captured response, nil if none was received.
+	Response *XHRResponse `json:"response"`
+}
+
+// XHRResponse is the response of a captured XHR call.
+type XHRResponse struct {
+	// Status is the HTTP status code.
+	Status int `json:"status"`
+	// Headers are the response headers.
+	Headers map[string]string `json:"headers"`
+	// Body is the response body.
+	Body string `json:"body"`
+	// ContentType is the response content type.
+	ContentType string `json:"content_type"`
+	// ContentEncoding is the response content encoding, if any.
+	ContentEncoding *string `json:"content_encoding"`
+	// Format is the encoding of Body ("text" or "binary").
+	Format string `json:"format"`
+	// Duration is the response time in seconds.
+	Duration float64 `json:"duration"`
+}
+
+// XHRCalls decodes the XHR / fetch calls captured during rendering.
+// This gives access to the API responses a single page application used to
+// build its DOM, which are often easier to use than the rendered HTML.
+//
+// Example:
+//
+//	calls, err := result.Result.BrowserData.XHRCalls()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, call := range calls {
+//	    if call.Response != nil && strings.Contains(call.URL, "/api/reviews") {
+//	        fmt.Println(call.Response.Body)
+//	    }
+//	}
+func (b *BrowserData) XHRCalls() ([]XHRCall, error) {
+	calls := []XHRCall{}
+	if len(b.XHRCall) == 0 {
+		return calls, nil
+	}
+	if err := remarshal(b.XHRCall, &calls); err != nil {
+		return nil, fmt.Errorf("failed to decode xhr_call: %w", err)
+	}
+	return calls, nil
+}
+
+// WebsocketMessage is a websocket frame captured by the browser during rendering.
+type WebsocketMessage struct {
+	// URL is the websocket URL the frame was exchanged on.
+	URL string `json:"url"`
+	// Direction is "sent" for frames sent by the page, "received" otherwise.
+	Direction string `json:"direction"`
+	// Payload is the frame payload.
+	Payload string `json:"payload"`
+	// Timestamp is the frame time as a unix timestamp in seconds.
+	Timestamp float64 `json:"timestamp"`
+}
+
+// WebsocketMessages decodes the websocket frames captured during rendering.
+// Returns an empty slice when no websocket was opened by the page.
+func (b *BrowserData) WebsocketMessages() ([]WebsocketMessage, error) {
+	messages := []WebsocketMessage{}
+	if len(b.Websockets) == 0 {
+		return messages, nil
+	}
+	if err := remarshal(b.Websockets, &messages); err != nil {
+		return nil, fmt.Errorf("failed to decode websockets: %w", err)
+	}
+	return messages, nil
+}
+
+// LocalStorageString returns the raw value stored under key in the page's
+// localStorage. The second return value is false when the key is missing.
+func (b *BrowserData) LocalStorageString(key string) (string, bool) {
+	return storageString(b.LocalStorageData, key)
+}
+
+// SessionStorageString returns the raw value stored under key in the page's
+// sessionStorage. The second return value is false when the key is missing.
+func (b *BrowserData) SessionStorageString(key string) (string, bool) {
+	return storageString(b.SessionStorageData, key)
+}
+
+// UnmarshalLocalStorage decodes the JSON value stored under key in the page's
+// localStorage into out.
+//
+// Example:
+//
+//	var cart struct {
+//	    Items []string `json:"items"`
+//	}
+//	if err := result.Result.BrowserData.UnmarshalLocalStorage("cart", &cart); err != nil {
+//	    log.Fatal(err)
+//	}
+func (b *BrowserData) UnmarshalLocalStorage(key string, out interface{}) error {
+	return unmarshalStorage(b.LocalStorageData, "local_storage_data", key, out)
+}
+
+// UnmarshalSessionStorage decodes the JSON value stored under key in the
+// page's sessionStorage into out.
+func (b *BrowserData) UnmarshalSessionStorage(key string, out interface{}) error {
+	return unmarshalStorage(b.SessionStorageData, "session_storage_data", key, out)
+}
+
+func storageString(storage map[string]interface{}, key string) (string, bool) {
+	value, ok := storage[key]
+	if !ok {
+		return "", false
+	}
+	if s, isString := value.(string); isString {
+		return s, true
+	}
+	return fmt.Sprint(value), true
+}
+
+// unmarshalStorage decodes a web storage value. Web storage only holds
+// strings, so values are usually JSON documents encoded as a string; values
+// the API already decoded are converted as is.
+func unmarshalStorage(storage map[string]interface{}, name, key string, out interface{}) error {
+	value, ok := storage[key]
+	if !ok {
+		return fmt.Errorf("%s: key %q not found", name, key)
+	}
+	var err error
+	if s, isString := value.(string); isString {
+		err = json.Unmarshal([]byte(s), out)
+	} else {
+		err = remarshal(value, out)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: failed to decode key %q: %w", name, key, err)
+	}
+	return nil
+}
+
+// harLog is the HAR 1.2 document built by BrowserData.AsHAR.
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string       `json:"startedDateTime"`
+	Time            float64      `json:"time"`
+	Request         harRequest   `json:"request"`
+	Response        harResponse  `json:"response"`
+	Cache           struct{}     `json:"cache"`
+	Timings         harTimings   `json:"timings"`
+	ResourceType    string       `json:"_resourceType,omitempty"`
+	WebSocket       []harWSFrame `json:"_webSocketMessages,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// harWSFrame is a websocket frame in the Chrome DevTools HAR extension.
+type harWSFrame struct {
+	Type   string  `json:"type"`
+	Time   float64 `json:"time"`
+	Opcode int     `json:"opcode"`
+	Data   string  `json:"data"`
+}
+
+// AsHAR assembles the XHR / fetch calls and websocket frames captured during
+// rendering into a HAR 1.2 document, which can be imported in Chrome
+// DevTools or any HAR viewer.
+//
+// The API does not report when XHR calls started, so their startedDateTime
+// is the unix epoch and only their duration is meaningful. Websocket frames
+// are grouped in one entry per websocket URL, using the DevTools
+// "_webSocketMessages" extension.
+//
+// Example:
+//
+//	har, err := result.Result.BrowserData.AsHAR()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile("capture.har", har, 0644)
+func (b *BrowserData) AsHAR() ([]byte, error) {
+	calls, err := b.XHRCalls()
+	if err != nil {
+		return nil, err
+	}
+	messages, err := b.WebsocketMessages()
+	if err != nil {
+		return nil, err
+	}
+
+	doc := harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: sdkUserAgent},
+		Entries: []harEntry{},
+	}
+	for _, call := range calls {
+		doc.Entries = append(doc.Entries, xhrHAREntry(call))
+	}
+	doc.Entries = append(doc.Entries, websocketHAREntries(messages)...)
+
+	return json.MarshalIndent(map[string]harLog{"log": doc}, "", "  ")
+}
+
+func xhrHAREntry(call XHRCall) harEntry {
+	method := call.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	entry := harEntry{
+		StartedDateTime: time.Unix(0, 0).UTC().Format(time.RFC3339),
+		Request: harRequest{
+			Method:      strings.ToUpper(method),
+			URL:         call.URL,
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(call.Headers),
+			QueryString: harQueryString(call.URL),
+			HeadersSize: -1,
+			BodySize:    0,
+		},
+		Response: harResponse{
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []harNameValue{},
+			Headers:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		ResourceType: call.Type,
+	}
+	if call.Body != nil {
+		entry.Request.BodySize = len(*call.Body)
+		entry.Request.PostData = &harPostData{MimeType: headerValue(call.Headers, "Content-Type"), Text: *call.Body}
+	}
+	if resp := call.Response; resp != nil {
+		entry.Time = resp.Duration * 1000
+		entry.Timings.Wait = entry.Time
+		entry.Response.Status = resp.Status
+		entry.Response.StatusText = http.StatusText(resp.Status)
+		entry.Response.Headers = harHeaders(resp.Headers)
+		entry.Response.BodySize = len(resp.Body)
+		entry.Response.Content = harContent{Size: len(resp.Body), MimeType: resp.ContentType, Text: resp.Body}
+		if resp.Format == "binary" {
+			entry.Response.Content.Encoding = "base64"
+		}
+	}
+	return entry
+}
+
+// websocketHAREntries groups websocket frames by URL, in order of first use.
+func websocketHAREntries(messages []WebsocketMessage) []harEntry {
+	var entries []harEntry
+	index := map[string]int{}
+	for _, message := range messages {
+		i, ok := index[message.URL]
+		if !ok {
+			started := time.Unix(0, int64(message.Timestamp*float64(time.Second))).UTC()
+			i = len(entries)
+			index[message.URL] = i
+			entries = append(entries, harEntry{
+				StartedDateTime: started.Format(time.RFC3339Nano),
+				Request: harRequest{
+					Method:      http.MethodGet,
+					URL:         message.URL,
+					HTTPVersion: "HTTP/1.1",
+					Cookies:     []harNameValue{},
+					Headers:     []harNameValue{},
+					QueryString: harQueryString(message.URL),
+					HeadersSize: -1,
+				},
+				Response: harResponse{
+					Status:      http.StatusSwitchingProtocols,
+					StatusText:  http.StatusText(http.StatusSwitchingProtocols),
+					HTTPVersion: "HTTP/1.1",
+					Cookies:     []harNameValue{},
+					Headers:     []harNameValue{},
+					HeadersSize: -1,
+				},
+				ResourceType: "websocket",
+			})
+		}
+		frameType := "receive"
+		if message.Direction == "sent" {
+			frameType = "send"
+		}
+		entries[i].WebSocket = append(entries[i].WebSocket, harWSFrame{
+			Type:   frameType,
+			Time:   message.Timestamp,
+			Opcode: 1,
+			Data:   message.Payload,
+		})
+	}
+	return entries
+}
+
+// harHeaders converts headers to HAR name/value pairs, sorted by name.
+func harHeaders(headers map[string]string) []harNameValue {
+	pairs := make([]harNameValue, 0, len(headers))
+	for name, value := range headers {
+		pairs = append(pairs, harNameValue{Name: name, Value: value})
+	}
+	slices.SortFunc(pairs, func(a, b harNameValue) int { return strings.Compare(a.Name, b.Name) })
+	return pairs
+}
+
+func harQueryString(rawURL string) []harNameValue {
+	pairs := []harNameValue{}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return pairs
+	}
+	query := u.Query()
+	for _, name := range slices.Sorted(maps.Keys(query)) {
+		for _, value := range query[name] {
+			pairs = append(pairs, harNameValue{Name: name, Value: value})
+		}
+	}
+	return pairs
+}
+
+// headerValue looks name up in headers case-insensitively.
+func headerValue(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
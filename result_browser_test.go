@@ -0,0 +1,337 @@
+package scrapfly
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// browserDataResponse is the browser_data block of a rendered scrape with a
+// click + wait scenario that triggered one XHR call.
+const browserDataResponse = `{
+  "javascript_evaluation_result": "2022-07-22",
+  "js_scenario": {
+    "duration": 3.07,
+    "executed": 2,
+    "response": null,
+    "steps": [
+      {
+        "action": "click",
+        "config": {"ignore_if_not_visible": false, "multiple": false, "selector": "#load-more-reviews", "timeout": 3500},
+        "duration": 1.07,
+        "executed": true,
+        "result": null,
+        "success": true
+      },
+      {
+        "action": "wait",
+        "config": 2000,
+        "duration": 2,
+        "executed": true,
+        "result": null,
+        "success": false
+      }
+    ]
+  },
+  "local_storage_data": {},
+  "session_storage_data": {},
+  "websockets": [],
+  "xhr_call": [
+    {
+      "body": null,
+      "headers": {"Accept": "*/*", "x-csrf-token": "secret-csrf-token-123"},
+      "method": "GET",
+      "response": {
+        "body": "{\"page_number\":2,\"results\":[]}",
+        "content_encoding": null,
+        "content_type": "application/json",
+        "duration": 0,
+        "format": "text",
+        "headers": {"content-type": "application/json"},
+        "status": 200
+      },
+      "type": "fetch",
+      "url": "https://web-scraping.dev/api/reviews?product_id=1&page=2"
+    }
+  ]
+}`
+
+func parseBrowserData(t *testing.T) *BrowserData {
+	t.Helper()
+	var data BrowserData
+	if err := json.Unmarshal([]byte(browserDataResponse), &data); err != nil {
+		t.Fatal(err)
+	}
+	return &data
+}
+
+func TestBrowserData_ScenarioResult(t *testing.T) {
+	report, err := parseBrowserData(t).ScenarioResult()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Executed != 2 || len(report.Steps) != 2 {
+		t.Fatalf("executed=%d steps=%d", report.Executed, len(report.Steps))
+	}
+	click := report.Steps[0]
+	if click.Action != "click" || !click.Success || click.Duration != 1.07 {
+		t.Errorf("click step = %+v", click)
+	}
+	if cfg, ok := click.Config.(map[string]interface{}); !ok || cfg["selector"] != "#load-more-reviews" {
+		t.Errorf("click config = %v", click.Config)
+	}
+	if wait := report.Steps[1]; wait.Action != "wait" || wait.Success || wait.Config != float64(2000) {
+		t.Errorf("wait step = %+v", wait)
+	}
+}
+
+func TestJSScenarioResult_NavigationResponse(t *testing.T) {
+	var data BrowserData
+	if err := json.Unmarshal([]byte(`{"js_scenario": {
+  "duration": 2.1,
+  "executed": 3,
+  "response": null,
+  "steps": [
+    {"action": "fill", "config": {"selector": "input[name=username]", "value": "user123"}, "duration": 0.1, "executed": true, "result": null, "success": true, "response": null},
+    {
+      "action": "click",
+      "config": {"selector": "form button[type='submit']"},
+      "duration": 1.4,
+      "executed": true,
+      "result": null,
+      "success": true,
+      "response": {
+        "url": "https://web-scraping.dev/login?cookies=",
+        "status": 200,
+        "headers": {"content-type": "text/html; charset=utf-8"},
+        "body": "<html>Logged in</html>",
+        "content_type": "text/html",
+        "format": "text"
+      }
+    },
+    {"action": "wait_for_navigation", "config": {"timeout": 5000}, "duration": 0.6, "executed": true, "result": null, "success": true}
+  ]
+}}`), &data); err != nil {
+		t.Fatal(err)
+	}
+	report, err := data.ScenarioResult()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if step, err := report.Steps[0].NavigationResponse(); step != nil || err != nil {
+		t.Errorf("fill step response = %+v, %v", step, err)
+	}
+	if step, err := report.Steps[1].NavigationResponse(); err != nil || step == nil || step.Status != 200 {
+		t.Fatalf("click step response = %+v, %v", step, err)
+	}
+	response, err := report.NavigationResponse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response == nil || response.Status != 200 || response.URL != "https://web-scraping.dev/login?cookies=" || response.Body != "<html>Logged in</html>" {
+		t.Errorf("NavigationResponse() = %+v", response)
+	}
+
+	report.Response = map[string]interface{}{"url": "https://web-scraping.dev/", "status": 302}
+	if response, err := report.NavigationResponse(); err != nil || response.Status != 302 {
+		t.Errorf("NavigationResponse() = %+v, %v, want the scenario response", response, err)
+	}
+
+	// An unexpected shape only fails the accessor, not the report.
+	if err := json.Unmarshal([]byte(`{"js_scenario": {"steps": [{"action": "click", "response": {"status": "200", "headers": {"set-cookie": ["a=1", "b=2"]}}}]}}`), &data); err != nil {
+		t.Fatal(err)
+	}
+	report, err = data.ScenarioResult()
+	if err != nil {
+		t.Fatalf("ScenarioResult() failed on an unexpected step response: %v", err)
+	}
+	if _, err := report.Steps[0].NavigationResponse(); err == nil {
+		t.Error("expected a decoding error from NavigationResponse")
+	}
+
+	none, err := parseBrowserData(t).ScenarioResult()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response, err := none.NavigationResponse(); response != nil || err != nil {
+		t.Errorf("expected nil, nil; got %v, %v", response, err)
+	}
+}
+
+func TestBrowserData_ScenarioResultAbsent(t *testing.T) {
+	report, err := (&BrowserData{}).ScenarioResult()
+	if err != nil || report != nil {
+		t.Errorf("expected nil, nil; got %v, %v", report, err)
+	}
+}
+
+func TestBrowserData_XHRCalls(t *testing.T) {
+	calls, err := parseBrowserData(t).XHRCalls()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 call, got %d", len(calls))
+	}
+	call := calls[0]
+	if call.Method != "GET" || call.Type != "fetch" || call.URL != "https://web-scraping.dev/api/reviews?product_id=1&page=2" {
+		t.Errorf("call = %+v", call)
+	}
+	if call.Body != nil {
+		t.Errorf("body should be nil, got %q", *call.Body)
+	}
+	if call.Headers["x-csrf-token"] != "secret-csrf-token-123" {
+		t.Errorf("headers = %v", call.Headers)
+	}
+	if call.Response == nil || call.Response.Status != 200 || call.Response.ContentType != "application/json" {
+		t.Fatalf("response = %+v", call.Response)
+	}
+	var payload struct {
+		PageNumber int `json:"page_number"`
+	}
+	if err := json.Unmarshal([]byte(call.Response.Body), &payload); err != nil || payload.PageNumber != 2 {
+		t.Errorf("response body not usable: %v %+v", err, payload)
+	}
+}
+
+func TestBrowserData_XHRCallsEmpty(t *testing.T) {
+	calls, err := (&BrowserData{}).XHRCalls()
+	if err != nil || calls == nil || len(calls) != 0 {
+		t.Errorf("expected empty slice, got %v, %v", calls, err)
+	}
+}
+
+func TestBrowserData_WebsocketMessages(t *testing.T) {
+	var data BrowserData
+	err := json.Unmarshal([]byte(`{"websockets": [
+		{"url": "wss://example.com/live", "direction": "sent", "payload": "{\"subscribe\":\"ticker\"}", "timestamp": 1730000000.5},
+		{"url": "wss://example.com/live", "direction": "received", "payload": "{\"price\":42}", "timestamp": 1730000001}
+	]}`), &data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages, err := data.WebsocketMessages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(messages))
+	}
+	if messages[0].Direction != "sent" || messages[0].Timestamp != 1730000000.5 {
+		t.Errorf("first message = %+v", messages[0])
+	}
+	if messages[1].Payload != `{"price":42}` || messages[1].URL != "wss://example.com/live" {
+		t.Errorf("second message = %+v", messages[1])
+	}
+}
+
+func TestBrowserData_WebsocketMessagesEmpty(t *testing.T) {
+	messages, err := parseBrowserData(t).WebsocketMessages()
+	if err != nil || messages == nil || len(messages) != 0 {
+		t.Errorf("expected empty slice, got %v, %v", messages, err)
+	}
+}
+
+func TestBrowserData_Storage(t *testing.T) {
+	data := &BrowserData{
+		LocalStorageData: map[string]interface{}{
+			"token": "abc123",
+			"cart":  `{"items": ["a", "b"], "total": 12.5}`,
+		},
+		SessionStorageData: map[string]interface{}{
+			"visits": float64(3),
+		},
+	}
+
+	if token, ok := data.LocalStorageString("token"); !ok || token != "abc123" {
+		t.Errorf("token = %q, %v", token, ok)
+	}
+	if _, ok := data.LocalStorageString("missing"); ok {
+		t.Error("missing key reported as present")
+	}
+
+	var cart struct {
+		Items []string `json:"items"`
+		Total float64  `json:"total"`
+	}
+	if err := data.UnmarshalLocalStorage("cart", &cart); err != nil {
+		t.Fatal(err)
+	}
+	if len(cart.Items) != 2 || cart.Total != 12.5 {
+		t.Errorf("cart = %+v", cart)
+	}
+
+	var visits int
+	if err := data.UnmarshalSessionStorage("visits", &visits); err != nil || visits != 3 {
+		t.Errorf("visits = %d, %v", visits, err)
+	}
+	if err := data.UnmarshalSessionStorage("missing", &visits); err == nil {
+		t.Error("expected error for missing key")
+	}
+}
+
+func TestBrowserData_AsHAR(t *testing.T) {
+	data := parseBrowserData(t)
+	data.Websockets = []interface{}{
+		map[string]interface{}{"url": "wss://web-scraping.dev/ws", "direction": "sent", "payload": "ping", "timestamp": 1700000000.5},
+		map[string]interface{}{"url": "wss://web-scraping.dev/ws", "direction": "received", "payload": "pong", "timestamp": 1700000001.0},
+	}
+
+	raw, err := data.AsHAR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := ParseHAR(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archive.Version() != "1.2" || archive.Len() != 2 {
+		t.Fatalf("version = %q, entries = %d", archive.Version(), archive.Len())
+	}
+
+	xhr := archive.FindByURL("https://web-scraping.dev/api/reviews?product_id=1&page=2")
+	if xhr == nil {
+		t.Fatalf("xhr entry missing: %v", archive.URLs())
+	}
+	if xhr.Method() != "GET" || xhr.StatusCode() != 200 || xhr.ContentType() != "application/json" {
+		t.Errorf("xhr entry = %s", xhr)
+	}
+	if string(xhr.Content()) != `{"page_number":2,"results":[]}` {
+		t.Errorf("xhr content = %q", xhr.Content())
+	}
+	if xhr.RequestHeaders()["x-csrf-token"] != "secret-csrf-token-123" {
+		t.Errorf("xhr request headers = %v", xhr.RequestHeaders())
+	}
+
+	var doc struct {
+		Log struct {
+			Entries []struct {
+				Request struct {
+					QueryString []harNameValue `json:"queryString"`
+				} `json:"request"`
+				ResourceType string       `json:"_resourceType"`
+				Messages     []harWSFrame `json:"_webSocketMessages"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if query := doc.Log.Entries[0].Request.QueryString; len(query) != 2 || query[0].Name != "page" || query[1].Value != "1" {
+		t.Errorf("query string = %+v", query)
+	}
+	ws := doc.Log.Entries[1]
+	if ws.ResourceType != "websocket" || len(ws.Messages) != 2 || ws.Messages[0].Type != "send" || ws.Messages[1].Data != "pong" {
+		t.Errorf("websocket entry = %+v", ws)
+	}
+}
+
+func TestBrowserData_AsHAREmpty(t *testing.T) {
+	raw, err := (&BrowserData{}).AsHAR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := ParseHAR(raw)
+	if err != nil || archive.Len() != 0 {
+		t.Errorf("archive = %v, err = %v", archive, err)
+	}
+}
@@ -0,0 +1,261 @@
+package scrapfly
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExtractionImage is an image reference found by an extraction model.
+type ExtractionImage struct {
+	URL string `json:"url"`
+}
+
+// Article is the typed shape of the data returned by ExtractionModelArticle.
+// see https://scrapfly.io/docs/extraction-api/automatic-ai#models
+//
+// Fields the model could not find are returned as null by the API and
+// decode to their zero value (or nil for pointer fields).
+type Article struct {
+	Title         string            `json:"title"`
+	Headline      *string           `json:"headline"`
+	Description   *string           `json:"description"`
+	Author        *string           `json:"author"`
+	PublishedDate *string           `json:"published_date"`
+	ModifiedDate  *string           `json:"modified_date"`
+	Body          string            `json:"body"`
+	BodyMarkdown  string            `json:"body_markdown"`
+	Language      *string           `json:"language"`
+	CanonicalURL  *string           `json:"canonical_url"`
+	MainImage     *string           `json:"main_image"`
+	Images        []ExtractionImage `json:"images"`
+	Keywords      []string          `json:"keywords"`
+}
+
+// AsArticle decodes the extracted data into an Article.
+// Use it on the result of an extraction made with ExtractionModelArticle.
+// It returns ErrNoExtractedData when the result has no data.
+//
+// Example:
+//
+//	result, err := client.Extract(&scrapfly.ExtractionConfig{
+//	    Body:            []byte(html),
+//	    ContentType:     "text/html",
+//	    ExtractionModel: scrapfly.ExtractionModelArticle,
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	article, err := result.AsArticle()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(article.Title)
+func (r *ExtractionResult) AsArticle() (*Article, error) {
+	var article Article
+	if err := decodeExtractionData(r.Data, &article); err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
+// ProductBreadcrumb is one level of a product page breadcrumb trail.
+type ProductBreadcrumb struct {
+	Name string  `json:"name"`
+	Link *string `json:"link"`
+}
+
+// ProductRating is the aggregate review rating of a product.
+type ProductRating struct {
+	BestRating  *float64 `json:"best_rating"`
+	RatingValue *float64 `json:"rating_value"`
+	ReviewCount *int     `json:"review_count"`
+}
+
+// ProductSpecification is a name/value product attribute.
+type ProductSpecification struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Offer is a price offer for a product.
+// Price is the current selling price and RegularPrice the price before any
+// discount; either may be nil when the model could not find it.
+type Offer struct {
+	Availability string   `json:"availability"`
+	Currency     string   `json:"currency"`
+	Price        *float64 `json:"price"`
+	RegularPrice *float64 `json:"regular_price"`
+}
+
+// LowestPrice returns the lowest known price of the offer: the smaller of
+// Price and RegularPrice. ok is false when neither is set.
+func (o Offer) LowestPrice() (price float64, ok bool) {
+	switch {
+	case o.Price != nil && o.RegularPrice != nil:
+		return min(*o.Price, *o.RegularPrice), true
+	case o.Price != nil:
+		return *o.Price, true
+	case o.RegularPrice != nil:
+		return *o.RegularPrice, true
+	}
+	return 0, false
+}
+
+// IsDiscounted reports whether the offer's Price is lower than its RegularPrice.
+func (o Offer) IsDiscounted() bool {
+	return o.Price != nil && o.RegularPrice != nil && *o.Price < *o.RegularPrice
+}
+
+// InStock reports whether the offer can be bought. Both the model's own
+// values ("available") and schema.org availability values ("InStock",
+// "https://schema.org/InStock") are recognised.
+func (o Offer) InStock() bool {
+	availability := strings.ToLower(o.Availability)
+	availability = availability[strings.LastIndex(availability, "/")+1:]
+	switch availability {
+	case "available", "instock", "in_stock", "in stock", "limitedavailability", "onlineonly":
+		return true
+	}
+	return false
+}
+
+// ProductVariant is a variant (color, size...) of a product.
+type ProductVariant struct {
+	Color  *string `json:"color"`
+	Size   *string `json:"size"`
+	SKU    *string `json:"sku"`
+	URL    *string `json:"url"`
+	Offers []Offer `json:"offers"`
+}
+
+// Product is the typed shape of the data returned by ExtractionModelProduct.
+// see https://scrapfly.io/docs/extraction-api/automatic-ai#models
+//
+// Fields the model could not find are returned as null by the API and
+// decode to their zero value (or nil for pointer fields).
+type Product struct {
+	Name                string                 `json:"name"`
+	Brand               *string                `json:"brand"`
+	Description         *string                `json:"description"`
+	DescriptionMarkdown *string                `json:"description_markdown"`
+	URL                 *string                `json:"url"`
+	CanonicalURL        *string                `json:"canonical_url"`
+	MainCategory        *string                `json:"main_category"`
+	SecondaryCategory   *string                `json:"secondary_category"`
+	MainImage           *string                `json:"main_image"`
+	Images              []ExtractionImage      `json:"images"`
+	Breadcrumbs         []ProductBreadcrumb    `json:"breadcrumbs"`
+	AggregateRating     *ProductRating         `json:"aggregate_rating"`
+	Identifiers         map[string]*string     `json:"identifiers"`
+	Offers              []Offer                `json:"offers"`
+	Specifications      []ProductSpecification `json:"specifications"`
+	Variants            []ProductVariant       `json:"variants"`
+	Color               *string                `json:"color"`
+	Size                *string                `json:"size"`
+	Style               *string                `json:"style"`
+	Delivery            *string                `json:"delivery"`
+}
+
+// PrimaryOffer returns the first in-stock offer of the product, or nil when
+// no offer is in stock.
+//
+// Example:
+//
+//	product, _ := result.AsProduct()
+//	if offer := product.PrimaryOffer(); offer != nil {
+//	    price, _ := offer.LowestPrice()
+//	    fmt.Println(price, offer.Currency)
+//	}
+func (p *Product) PrimaryOffer() *Offer {
+	for i := range p.Offers {
+		if p.Offers[i].InStock() {
+			return &p.Offers[i]
+		}
+	}
+	return nil
+}
+
+// LowestPrice returns the lowest known price across all offers of the
+// product, in stock or not. ok is false when no offer has a price.
+func (p *Product) LowestPrice() (price float64, ok bool) {
+	for _, offer := range p.Offers {
+		if offerPrice, found := offer.LowestPrice(); found && (!ok || offerPrice < price) {
+			price, ok = offerPrice, true
+		}
+	}
+	return price, ok
+}
+
+// AsProduct decodes the extracted data into a Product.
+// Use it on the result of an extraction made with ExtractionModelProduct.
+// It returns ErrNoExtractedData when the result has no data.
+//
+// Example:
+//
+//	result, err := client.Extract(&scrapfly.ExtractionConfig{
+//	    Body:            []byte(html),
+//	    ContentType:     "text/html",
+//	    ExtractionModel: scrapfly.ExtractionModelProduct,
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	product, err := result.AsProduct()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(product.Name)
+func (r *ExtractionResult) AsProduct() (*Product, error) {
+	var product Product
+	if err := decodeExtractionData(r.Data, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// decodeExtractionData converts the loosely typed extraction data into out.
+// Absent or null data is reported as ErrNoExtractedData.
+func decodeExtractionData(data interface{}, out interface{}) error {
+	if data == nil {
+		return ErrNoExtractedData
+	}
+	if err := remarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode extraction data: %w", err)
+	}
+	return nil
+}
+
+// DecodeExtracted decodes the data of a scrape made with inline extraction
+// (ExtractionTemplate, ExtractionEphemeralTemplate, ExtractionPrompt or
+// ExtractionModel) into T. It returns ErrDataNotRequested when the scrape
+// was made without extraction, and ErrNoExtractedData when the extraction
+// returned no data.
+//
+// Example:
+//
+//	type Item struct {
+//	    Name  string `json:"name"`
+//	    Brand string `json:"brand"`
+//	}
+//	result, err := client.Scrape(&scrapfly.ScrapeConfig{
+//	    URL:             "https://web-scraping.dev/product/1",
+//	    ExtractionModel: scrapfly.ExtractionModelProduct,
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	item, err := scrapfly.DecodeExtracted[Item](result)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(item.Name, item.Brand)
+func DecodeExtracted[T any](r *ScrapeResult) (T, error) {
+	var out T
+	if r.Result.ExtractedData == nil {
+		return out, fmt.Errorf("%w: no extracted data, set an extraction option on the ScrapeConfig", ErrDataNotRequested)
+	}
+	if err := decodeExtractionData(r.Result.ExtractedData.Data, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
@@ -0,0 +1,214 @@
+package scrapfly
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+const articleExtractionResponse = `{
+  "content_type": "application/json",
+  "data": {
+    "title": "How to Scrape Without Getting Blocked",
+    "headline": "How to Scrape Without Getting Blocked",
+    "description": "A tour of the most common blocking techniques.",
+    "author": "Jane Doe",
+    "published_date": "2024-05-02T08:00:00Z",
+    "modified_date": null,
+    "body": "Web scraping is...",
+    "body_markdown": "# How to Scrape\n\nWeb scraping is...",
+    "language": "en",
+    "canonical_url": "https://example.com/blog/scraping",
+    "main_image": "https://example.com/img/cover.webp",
+    "images": [
+      {"url": "https://example.com/img/cover.webp"},
+      {"url": "https://example.com/img/diagram.png"}
+    ],
+    "keywords": ["scraping", "proxies"]
+  },
+  "data_quality": null
+}`
+
+func TestExtractionResult_AsArticle(t *testing.T) {
+	var result ExtractionResult
+	if err := json.Unmarshal([]byte(articleExtractionResponse), &result); err != nil {
+		t.Fatal(err)
+	}
+	article, err := result.AsArticle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Title != "How to Scrape Without Getting Blocked" {
+		t.Errorf("title = %q", article.Title)
+	}
+	if article.Author == nil || *article.Author != "Jane Doe" {
+		t.Errorf("author = %v", article.Author)
+	}
+	if article.PublishedDate == nil || *article.PublishedDate != "2024-05-02T08:00:00Z" {
+		t.Errorf("published_date = %v", article.PublishedDate)
+	}
+	if article.ModifiedDate != nil {
+		t.Errorf("modified_date should be nil, got %v", *article.ModifiedDate)
+	}
+	if article.BodyMarkdown == "" || article.Body == "" {
+		t.Error("body / body_markdown not decoded")
+	}
+	if len(article.Images) != 2 || article.Images[1].URL != "https://example.com/img/diagram.png" {
+		t.Errorf("images = %+v", article.Images)
+	}
+	if article.Language == nil || *article.Language != "en" {
+		t.Errorf("language = %v", article.Language)
+	}
+}
+
+func TestExtractionResult_AsArticleNoData(t *testing.T) {
+	result := &ExtractionResult{}
+	if _, err := result.AsArticle(); !errors.Is(err, ErrNoExtractedData) {
+		t.Errorf("expected ErrNoExtractedData, got %v", err)
+	}
+
+	var null ExtractionResult
+	if err := json.Unmarshal([]byte(`{"content_type": "application/json", "data": null}`), &null); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := null.AsProduct(); !errors.Is(err, ErrNoExtractedData) {
+		t.Errorf("null data: expected ErrNoExtractedData, got %v", err)
+	}
+}
+
+const productExtractionResponse = `{
+  "content_type": "application/json",
+  "data": {
+    "name": "Box of Chocolate Candy",
+    "brand": "ChocoDelight",
+    "aggregate_rating": {"best_rating": 5, "rating_value": 4.7, "review_count": 10},
+    "breadcrumbs": [{"link": "/", "name": "Home"}, {"link": null, "name": "Box of Chocolate Candy"}],
+    "identifiers": {"sku": "1", "upc": null},
+    "images": [{"url": "https://www.web-scraping.dev/assets/products/orange-chocolate-box-small-1.webp"}],
+    "offers": [
+      {"availability": "out_of_stock", "currency": "USD", "price": 7.99, "regular_price": null},
+      {"availability": "available", "currency": "USD", "price": 9.99, "regular_price": 12.99}
+    ],
+    "variants": [{"color": "orange", "offers": [], "sku": null, "url": "https://web-scraping.dev/product/1?variant=orange-small"}]
+  },
+  "data_quality": null
+}`
+
+func TestExtractionResult_AsProduct(t *testing.T) {
+	var result ExtractionResult
+	if err := json.Unmarshal([]byte(productExtractionResponse), &result); err != nil {
+		t.Fatal(err)
+	}
+	product, err := result.AsProduct()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if product.Name != "Box of Chocolate Candy" || product.Brand == nil || *product.Brand != "ChocoDelight" {
+		t.Errorf("name = %q, brand = %v", product.Name, product.Brand)
+	}
+	if product.AggregateRating == nil || *product.AggregateRating.ReviewCount != 10 {
+		t.Errorf("aggregate_rating = %+v", product.AggregateRating)
+	}
+	if len(product.Breadcrumbs) != 2 || product.Breadcrumbs[1].Link != nil {
+		t.Errorf("breadcrumbs = %+v", product.Breadcrumbs)
+	}
+	if product.Identifiers["sku"] == nil || *product.Identifiers["sku"] != "1" {
+		t.Errorf("identifiers = %v", product.Identifiers)
+	}
+	if len(product.Offers) != 2 || *product.Offers[1].RegularPrice != 12.99 {
+		t.Errorf("offers = %+v", product.Offers)
+	}
+	if len(product.Variants) != 1 || *product.Variants[0].Color != "orange" {
+		t.Errorf("variants = %+v", product.Variants)
+	}
+}
+
+func TestProduct_PrimaryOfferAndLowestPrice(t *testing.T) {
+	var result ExtractionResult
+	if err := json.Unmarshal([]byte(productExtractionResponse), &result); err != nil {
+		t.Fatal(err)
+	}
+	product, err := result.AsProduct()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	offer := product.PrimaryOffer()
+	if offer == nil || *offer.Price != 9.99 {
+		t.Fatalf("primary offer = %+v", offer)
+	}
+	if price, ok := offer.LowestPrice(); !ok || price != 9.99 {
+		t.Errorf("offer lowest price = %v, %v", price, ok)
+	}
+	if !offer.IsDiscounted() {
+		t.Error("expected offer to be discounted")
+	}
+	if price, ok := product.LowestPrice(); !ok || price != 7.99 {
+		t.Errorf("product lowest price = %v, %v", price, ok)
+	}
+
+	if (&Product{Offers: []Offer{{Availability: "OutOfStock"}}}).PrimaryOffer() != nil {
+		t.Error("expected no primary offer when nothing is in stock")
+	}
+	if _, ok := (&Product{}).LowestPrice(); ok {
+		t.Error("expected no lowest price without offers")
+	}
+}
+
+func TestOffer_LowestPrice(t *testing.T) {
+	price, regular := 15.0, 10.0
+	if got, ok := (Offer{Price: &price, RegularPrice: &regular}).LowestPrice(); !ok || got != 10 {
+		t.Errorf("lowest = %v, %v", got, ok)
+	}
+	if got, ok := (Offer{RegularPrice: &regular}).LowestPrice(); !ok || got != 10 {
+		t.Errorf("regular only = %v, %v", got, ok)
+	}
+	if _, ok := (Offer{}).LowestPrice(); ok {
+		t.Error("expected no price")
+	}
+}
+
+func TestOffer_InStock(t *testing.T) {
+	for availability, want := range map[string]bool{
+		"available":                  true,
+		"InStock":                    true,
+		"https://schema.org/InStock": true,
+		"out_of_stock":               false,
+		"https://schema.org/SoldOut": false,
+		"":                           false,
+	} {
+		if got := (Offer{Availability: availability}).InStock(); got != want {
+			t.Errorf("InStock(%q) = %v, want %v", availability, got, want)
+		}
+	}
+}
+
+func TestDecodeExtracted(t *testing.T) {
+	var extracted ExtractionResult
+	if err := json.Unmarshal([]byte(productExtractionResponse), &extracted); err != nil {
+		t.Fatal(err)
+	}
+	result := &ScrapeResult{Result: ResultData{ExtractedData: &extracted}}
+
+	type item struct {
+		Name   string `json:"name"`
+		Brand  string `json:"brand"`
+		Offers []struct {
+			Price float64 `json:"price"`
+		} `json:"offers"`
+	}
+	got, err := DecodeExtracted[item](result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "Box of Chocolate Candy" || got.Brand != "ChocoDelight" || len(got.Offers) != 2 || got.Offers[1].Price != 9.99 {
+		t.Errorf("DecodeExtracted = %+v", got)
+	}
+
+	if _, err := DecodeExtracted[item](&ScrapeResult{}); !errors.Is(err, ErrDataNotRequested) {
+		t.Errorf("expected ErrDataNotRequested, got %v", err)
+	}
+	if _, err := DecodeExtracted[item](&ScrapeResult{Result: ResultData{ExtractedData: &ExtractionResult{}}}); !errors.Is(err, ErrNoExtractedData) {
+		t.Errorf("expected ErrNoExtractedData, got %v", err)
+	}
+}
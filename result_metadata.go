@@ -0,0 +1,116 @@
+package scrapfly
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// JSONLD parses the schema.org JSON-LD blocks embedded in the page
+// (<script type="application/ld+json">) and returns their objects in
+// document order. A block holding an array contributes each of its objects.
+// Malformed blocks are skipped with a logged warning. It returns
+// ErrContentType when the content is not HTML.
+//
+// Example:
+//
+//	items, err := result.JSONLD()
+//	for _, item := range items {
+//	    if item["@type"] == "Product" {
+//	        fmt.Println(item["name"], item["offers"])
+//	    }
+//	}
+func (r *ScrapeResult) JSONLD() ([]map[string]interface{}, error) {
+	doc, err := r.Selector()
+	if err != nil {
+		return nil, err
+	}
+	items := []map[string]interface{}{}
+	doc.Find("script").Each(func(i int, s *goquery.Selection) {
+		scriptType, _ := s.Attr("type")
+		if !strings.EqualFold(strings.TrimSpace(scriptType), "application/ld+json") {
+			return
+		}
+		var block interface{}
+		if err := json.Unmarshal([]byte(s.Text()), &block); err != nil {
+			DefaultLogger.Warn("skipping malformed JSON-LD block", i, "of", r.Result.URL, ":", err)
+			return
+		}
+		switch v := block.(type) {
+		case map[string]interface{}:
+			items = append(items, v)
+		case []interface{}:
+			for _, entry := range v {
+				if item, ok := entry.(map[string]interface{}); ok {
+					items = append(items, item)
+				}
+			}
+		}
+	})
+	return items, nil
+}
+
+// MetaTags returns the content of the page <meta> tags keyed by their name
+// or property attribute, lowercased. When a tag is repeated (several
+// og:image for instance) the first one wins. Tags without a name, property
+// or content, such as charset declarations, are skipped. It returns
+// ErrContentType when the content is not HTML.
+//
+// Example:
+//
+//	tags, err := result.MetaTags()
+//	fmt.Println(tags["description"], tags["og:title"])
+func (r *ScrapeResult) MetaTags() (map[string]string, error) {
+	doc, err := r.Selector()
+	if err != nil {
+		return nil, err
+	}
+	tags := map[string]string{}
+	doc.Find("meta").Each(func(_ int, s *goquery.Selection) {
+		key, ok := s.Attr("property")
+		if !ok || strings.TrimSpace(key) == "" {
+			key, ok = s.Attr("name")
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		content, hasContent := s.Attr("content")
+		if !ok || key == "" || !hasContent {
+			return
+		}
+		if _, seen := tags[key]; !seen {
+			tags[key] = strings.TrimSpace(content)
+		}
+	})
+	return tags, nil
+}
+
+// OpenGraph returns the OpenGraph (og:*) meta tags with their prefix
+// trimmed, e.g. "title", "image" or "image:width". See MetaTags.
+//
+// Example:
+//
+//	og, err := result.OpenGraph()
+//	preview := Preview{Title: og["title"], Image: og["image"]}
+func (r *ScrapeResult) OpenGraph() (map[string]string, error) {
+	return r.prefixedMetaTags("og:")
+}
+
+// TwitterCard returns the Twitter card (twitter:*) meta tags with their
+// prefix trimmed, e.g. "card", "title" or "image". See MetaTags.
+func (r *ScrapeResult) TwitterCard() (map[string]string, error) {
+	return r.prefixedMetaTags("twitter:")
+}
+
+func (r *ScrapeResult) prefixedMetaTags(prefix string) (map[string]string, error) {
+	tags, err := r.MetaTags()
+	if err != nil {
+		return nil, err
+	}
+	prefixed := map[string]string{}
+	for key, content := range tags {
+		if name, ok := strings.CutPrefix(key, prefix); ok && name != "" {
+			prefixed[name] = content
+		}
+	}
+	return prefixed, nil
+}
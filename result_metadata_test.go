@@ -0,0 +1,87 @@
+package scrapfly
+
+import (
+	"errors"
+	"maps"
+	"testing"
+)
+
+func TestScrapeResult_JSONLD(t *testing.T) {
+	result := htmlResult("https://web-scraping.dev/product/1", `<html><head>
+<script type="application/ld+json">{"@context": "https://schema.org", "@type": "Product", "name": "Box of Chocolate Candy"}</script>
+<script type="application/ld+json">{"@type": "Product", "name": </script>
+<script type="text/javascript">var x = {"@type": "Script"};</script>
+</head><body>
+<script type="application/ld+json">[{"@type": "BreadcrumbList"}, {"@type": "Organization", "name": "ChocoDelight"}]</script>
+</body></html>`)
+
+	items, err := result.JSONLD()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []interface{}
+	for _, item := range items {
+		types = append(types, item["@type"])
+	}
+	if len(items) != 3 || types[0] != "Product" || types[1] != "BreadcrumbList" || types[2] != "Organization" {
+		t.Fatalf("JSONLD() types = %v", types)
+	}
+	if items[0]["name"] != "Box of Chocolate Candy" {
+		t.Errorf("product name = %v", items[0]["name"])
+	}
+
+	text := &ScrapeResult{Result: ResultData{ContentType: "application/json", Content: "{}"}}
+	if _, err := text.JSONLD(); !errors.Is(err, ErrContentType) {
+		t.Errorf("expected ErrContentType, got %v", err)
+	}
+}
+
+func TestScrapeResult_MetaTags(t *testing.T) {
+	result := htmlResult("https://web-scraping.dev/product/1", `<html><head>
+<meta charset="utf-8">
+<meta name="Description" content=" Chocolate candy box ">
+<meta property="og:title" content="Box of Chocolate Candy">
+<meta property="og:image" content="https://web-scraping.dev/1.webp">
+<meta property="og:image" content="https://web-scraping.dev/2.webp">
+<meta property="og:image:width" content="600">
+<meta name="twitter:card" content="summary_large_image">
+<meta name="twitter:title" content="Chocolate">
+<meta name="robots">
+</head></html>`)
+
+	tags, err := result.MetaTags()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"description":    "Chocolate candy box",
+		"og:title":       "Box of Chocolate Candy",
+		"og:image":       "https://web-scraping.dev/1.webp",
+		"og:image:width": "600",
+		"twitter:card":   "summary_large_image",
+		"twitter:title":  "Chocolate",
+	}
+	if !maps.Equal(tags, want) {
+		t.Errorf("MetaTags() = %v, want %v", tags, want)
+	}
+
+	og, err := result.OpenGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(og, map[string]string{"title": "Box of Chocolate Candy", "image": "https://web-scraping.dev/1.webp", "image:width": "600"}) {
+		t.Errorf("OpenGraph() = %v", og)
+	}
+	card, err := result.TwitterCard()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(card, map[string]string{"card": "summary_large_image", "title": "Chocolate"}) {
+		t.Errorf("TwitterCard() = %v", card)
+	}
+
+	text := &ScrapeResult{Result: ResultData{ContentType: "application/json", Content: "{}"}}
+	if _, err := text.MetaTags(); !errors.Is(err, ErrContentType) {
+		t.Errorf("expected ErrContentType, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package scrapfly
+
+import (
+	"fmt"
+)
+
+// SSLInfo describes the TLS connection to the target and its certificate,
+// captured when ScrapeConfig.SSL is enabled. Dates are reported as sent by
+// the API.
+type SSLInfo struct {
+	// Issuer is the distinguished name of the certificate issuer.
+	Issuer string `json:"issuer"`
+	// Subject is the distinguished name of the certificate subject.
+	Subject string `json:"subject"`
+	// ValidFrom is the start of the certificate validity period.
+	ValidFrom string `json:"valid_from"`
+	// ValidTo is the end of the certificate validity period.
+	ValidTo string `json:"valid_to"`
+	// SANs are the certificate subject alternative names.
+	SANs []string `json:"subject_alt_names"`
+	// Protocol is the negotiated TLS version, e.g. "TLSv1.3".
+	Protocol string `json:"protocol"`
+}
+
+// SSLInfo decodes the SSL details of the scrape. It returns an
+// ErrDataNotRequested error when the scrape was made without
+// ScrapeConfig.SSL.
+//
+// Example:
+//
+//	result, _ := client.Scrape(&scrapfly.ScrapeConfig{URL: "https://example.com", SSL: true})
+//	ssl, err := result.SSLInfo()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(ssl.Issuer, "valid until", ssl.ValidTo)
+func (r *ScrapeResult) SSLInfo() (*SSLInfo, error) {
+	if r.Result.SSL == nil {
+		return nil, fmt.Errorf("%w: no ssl data, enable ScrapeConfig.SSL", ErrDataNotRequested)
+	}
+	var ssl SSLInfo
+	if err := remarshal(r.Result.SSL, &ssl); err != nil {
+		return nil, fmt.Errorf("failed to decode ssl data: %w", err)
+	}
+	return &ssl, nil
+}
+
+// DNSInfo lists the DNS records of the target host by type, captured when
+// ScrapeConfig.DNS is enabled.
+type DNSInfo struct {
+	A     []string `json:"A"`
+	AAAA  []string `json:"AAAA"`
+	CNAME []string `json:"CNAME"`
+	MX    []string `json:"MX"`
+	TXT   []string `json:"TXT"`
+}
+
+// DNSInfo decodes the DNS details of the scrape. It returns an
+// ErrDataNotRequested error when the scrape was made without
+// ScrapeConfig.DNS.
+//
+// Example:
+//
+//	result, _ := client.Scrape(&scrapfly.ScrapeConfig{URL: "https://example.com", DNS: true})
+//	dns, err := result.DNSInfo()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println("resolves to", dns.A)
+func (r *ScrapeResult) DNSInfo() (*DNSInfo, error) {
+	if r.Result.DNS == nil {
+		return nil, fmt.Errorf("%w: no dns data, enable ScrapeConfig.DNS", ErrDataNotRequested)
+	}
+	var dns DNSInfo
+	if err := remarshal(r.Result.DNS, &dns); err != nil {
+		return nil, fmt.Errorf("failed to decode dns data: %w", err)
+	}
+	return &dns, nil
+}
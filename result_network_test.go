@@ -0,0 +1,74 @@
+package scrapfly
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestScrapeResult_SSLInfo(t *testing.T) {
+	var result ScrapeResult
+	body := `{"config": {"ssl": true}, "result": {"ssl": {
+		"issuer": "CN=R11, O=Let's Encrypt, C=US",
+		"subject": "CN=example.com",
+		"valid_from": "2024-01-01T00:00:00Z",
+		"valid_to": "2024-04-01T00:00:00Z",
+		"subject_alt_names": ["example.com", "www.example.com"],
+		"protocol": "TLSv1.3"
+	}}}`
+	if err := json.Unmarshal([]byte(body), &result); err != nil {
+		t.Fatal(err)
+	}
+	ssl, err := result.SSLInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &SSLInfo{
+		Issuer:    "CN=R11, O=Let's Encrypt, C=US",
+		Subject:   "CN=example.com",
+		ValidFrom: "2024-01-01T00:00:00Z",
+		ValidTo:   "2024-04-01T00:00:00Z",
+		SANs:      []string{"example.com", "www.example.com"},
+		Protocol:  "TLSv1.3",
+	}
+	if !reflect.DeepEqual(ssl, want) {
+		t.Errorf("ssl = %+v, want %+v", ssl, want)
+	}
+
+	if _, err := (&ScrapeResult{}).SSLInfo(); !errors.Is(err, ErrDataNotRequested) {
+		t.Errorf("expected ErrDataNotRequested, got %v", err)
+	}
+}
+
+func TestScrapeResult_DNSInfo(t *testing.T) {
+	var result ScrapeResult
+	body := `{"config": {"dns": true}, "result": {"dns": {
+		"A": ["93.184.215.14"],
+		"AAAA": ["2606:2800:21f:cb07:6820:80da:af6b:8b2c"],
+		"CNAME": [],
+		"MX": ["0 ."],
+		"TXT": ["v=spf1 -all", "_k2n1y4vw3qtb4skdx9e7dxt97qrmmq9"]
+	}}}`
+	if err := json.Unmarshal([]byte(body), &result); err != nil {
+		t.Fatal(err)
+	}
+	dns, err := result.DNSInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &DNSInfo{
+		A:     []string{"93.184.215.14"},
+		AAAA:  []string{"2606:2800:21f:cb07:6820:80da:af6b:8b2c"},
+		CNAME: []string{},
+		MX:    []string{"0 ."},
+		TXT:   []string{"v=spf1 -all", "_k2n1y4vw3qtb4skdx9e7dxt97qrmmq9"},
+	}
+	if !reflect.DeepEqual(dns, want) {
+		t.Errorf("dns = %+v, want %+v", dns, want)
+	}
+
+	if _, err := (&ScrapeResult{}).DNSInfo(); !errors.Is(err, ErrDataNotRequested) {
+		t.Errorf("expected ErrDataNotRequested, got %v", err)
+	}
+}
@@ -1,15 +1,26 @@
 package scrapfly
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html/charset"
 )
 
 // VerifyAPIKeyResult represents the result of an API key verification.
@@ -18,6 +29,16 @@ type VerifyAPIKeyResult struct {
 	Valid bool `json:"valid"`
 }
 
+// PingResult represents the result of a Client.Ping health check.
+type PingResult struct {
+	// Valid indicates whether the API key is valid.
+	Valid bool `json:"valid"`
+	// Latency is the round-trip time of the /account request.
+	Latency time.Duration `json:"latency"`
+	// Host is the API host the client is configured with.
+	Host string `json:"host"`
+}
+
 // ScrapeResult represents the complete response from a scrape request.
 //
 // It contains the scraped content, metadata, configuration, and context
@@ -36,6 +57,52 @@ type ScrapeResult struct {
 	selectorOnce sync.Once
 	selector     *goquery.Document
 	selectorErr  error
+
+	// rawContent is set when Content already holds the raw bytes of a
+	// large binary object fetched by the client.
+	rawContent bool
+	// client fetches deferred large objects, see ResolveLargeObject.
+	client *Client
+	// config is a copy of the config the result was scraped with, see Refresh.
+	config *ScrapeConfig
+}
+
+// scrapeResultJSON is the serialized form of a ScrapeResult, without its
+// unexported state.
+type scrapeResultJSON struct {
+	Config  ConfigData  `json:"config"`
+	Context ContextData `json:"context"`
+	Result  ResultData  `json:"result"`
+	UUID    string      `json:"uuid"`
+}
+
+// MarshalJSON encodes the result in the API response format, so it can be
+// stored and read back with json.Unmarshal. Raw binary content fetched from
+// a large object is base64 encoded, as the API does for binary responses,
+// so RawBytes returns the same bytes after a round-trip. Downloaded
+// screenshot images and attachment data are not included.
+func (r *ScrapeResult) MarshalJSON() ([]byte, error) {
+	out := scrapeResultJSON{Config: r.Config, Context: r.Context, Result: r.Result, UUID: r.UUID}
+	if r.rawContent {
+		out.Result.Content = base64.StdEncoding.EncodeToString([]byte(r.Result.Content))
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a result encoded by MarshalJSON or returned by the
+// API, resetting the document cached by Selector. A decoded result is not
+// attached to a Client: deferred large objects can't be resolved.
+func (r *ScrapeResult) UnmarshalJSON(data []byte) error {
+	var in scrapeResultJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	r.Config, r.Context, r.Result, r.UUID = in.Config, in.Context, in.Result, in.UUID
+	r.rawContent = false
+	r.selectorOnce = sync.Once{}
+	r.selector = nil
+	r.selectorErr = nil
+	return nil
 }
 
 // Selector provides a goquery document for parsing HTML content.
@@ -73,6 +140,439 @@ func (r *ScrapeResult) Selector() (*goquery.Document, error) {
 	return r.selector, r.selectorErr
 }
 
+// SetContent replaces Content and resets the document cached by Selector,
+// so Selector, Links, Title and the other helpers work on a result
+// rebuilt from storage, for instance one persisted without its HTML. The
+// content type defaults to text/html when the result has none.
+//
+// SetContent must not be called concurrently with the helpers.
+//
+// Example:
+//
+//	var result scrapfly.ScrapeResult
+//	_ = json.Unmarshal(metadata, &result)
+//	result.SetContent(string(html))
+//	title, _ := result.Title()
+func (r *ScrapeResult) SetContent(content string) {
+	r.Result.Content = content
+	if r.IsLargeObject() || r.Result.Format == "binary" {
+		r.Result.Format = "text"
+	}
+	if r.Result.ContentType == "" {
+		r.Result.ContentType = "text/html"
+	}
+	r.rawContent = false
+	r.selectorOnce = sync.Once{}
+	r.selector = nil
+	r.selectorErr = nil
+}
+
+// IsLargeObject reports whether Content is still the URL of a large object
+// (clob or blob format) rather than the content itself. This only happens
+// when the scrape was made with ScrapeConfig.DeferLargeObjects.
+func (r *ScrapeResult) IsLargeObject() bool {
+	return r.Result.Format == "clob" || r.Result.Format == "blob"
+}
+
+// ResolveLargeObject fetches a deferred large object and replaces Content
+// and Format with the object content, as Scrape does by default.
+// It does nothing when the content is not a large object.
+//
+// Example:
+//
+//	result, _ := client.Scrape(&scrapfly.ScrapeConfig{URL: url, DeferLargeObjects: true})
+//	if result.IsLargeObject() && needContent {
+//	    if err := result.ResolveLargeObject(); err != nil {
+//	        log.Fatal(err)
+//	    }
+//	}
+func (r *ScrapeResult) ResolveLargeObject() error {
+	if !r.IsLargeObject() {
+		return nil
+	}
+	return r.resolveLargeObject(context.Background())
+}
+
+func (r *ScrapeResult) resolveLargeObject(ctx context.Context) error {
+	if r.client == nil {
+		return fmt.Errorf("cannot resolve large object: result was not returned by a Client")
+	}
+	format := r.Result.Format
+	content, newFormat, err := r.client.handleLargeObjects(ctx, r.Result.Content, format)
+	if err != nil {
+		return fmt.Errorf("failed to fetch large object: %w", err)
+	}
+	r.Result.Content = content
+	r.Result.Format = newFormat
+	r.rawContent = format == "blob"
+	return nil
+}
+
+// Refresh scrapes the page again with the config of r and CacheClear set,
+// for a result served from the cache (see Context.Cache) that turned out to
+// be stale. The fresh result replaces the cached one. client is the client
+// to scrape with; when nil, the client that returned r is used.
+//
+// Results returned by Scrape keep a copy of their ScrapeConfig and are
+// refreshed with it as is. Results decoded from JSON don't: their config is
+// rebuilt from Config, the config the API echoes back, which doesn't carry
+// Format and FormatOptions, Cookies, custom Headers, the JS scenario,
+// extraction options or client-side options such as DeferLargeObjects.
+// Set them on a ScrapeConfig and call Scrape directly when they matter.
+//
+// Example:
+//
+//	if result.Context.Cache.State == "HIT" && isStale(result) {
+//	    result, err = result.Refresh(nil)
+//	}
+func (r *ScrapeResult) Refresh(client *Client) (*ScrapeResult, error) {
+	if client == nil {
+		client = r.client
+	}
+	if client == nil {
+		return nil, fmt.Errorf("cannot refresh: no client given and result was not returned by a Client")
+	}
+	var config *ScrapeConfig
+	if r.config != nil {
+		config = r.config.Clone()
+	} else {
+		config = r.Config.scrapeConfig()
+	}
+	config.Cache = true
+	config.CacheClear = true
+	return client.Scrape(config)
+}
+
+// RawBytes returns Content as bytes, decoding it when the API sent it base64
+// encoded (binary Format, or a "base64" ContentEncoding). Use it for binary
+// responses such as images or PDFs scraped directly.
+//
+// Example:
+//
+//	result, _ := client.Scrape(&scrapfly.ScrapeConfig{URL: "https://example.com/logo.png"})
+//	data, err := result.RawBytes()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile("logo.png", data, 0644)
+func (r *ScrapeResult) RawBytes() ([]byte, error) {
+	content := r.Result.Content
+	if r.rawContent {
+		return []byte(content), nil
+	}
+	if r.Result.Format == "binary" || strings.EqualFold(r.Result.ContentEncoding, "base64") {
+		data, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode base64 content: %w", err)
+		}
+		return data, nil
+	}
+	return []byte(content), nil
+}
+
+// BandwidthSaved estimates the bytes saved by a compressed upstream
+// transfer (see ScrapeConfig.CompressUpstream): the size of the decoded
+// content minus Context.BandwidthConsumed. It returns 0 when nothing was
+// saved, when the API did not report the bandwidth, or when the content is
+// an unresolved large object.
+//
+// BandwidthConsumed also counts response headers, so this is a lower bound.
+func (r *ScrapeResult) BandwidthSaved() int {
+	if r.Context.BandwidthConsumed == 0 || r.IsLargeObject() {
+		return 0
+	}
+	data, err := r.RawBytes()
+	if err != nil {
+		return 0
+	}
+	return max(len(data)-r.Context.BandwidthConsumed, 0)
+}
+
+// CacheState returns the cache state of the scrape as reported by the API
+// (e.g. "HIT", "MISS"), or "" when the cache was not enabled.
+func (r *ScrapeResult) CacheState() string {
+	return r.Context.Cache.State
+}
+
+// IsFromCache reports whether the content was served from the Scrapfly cache
+// rather than fetched from the target.
+func (r *ScrapeResult) IsFromCache() bool {
+	return strings.HasPrefix(strings.ToUpper(r.CacheState()), "HIT")
+}
+
+// UTF8Content returns the page decoded to UTF-8. Binary (base64 encoded)
+// content, as the API returns for pages it does not decode itself, is
+// decoded from RawBytes with the charset taken from the Content-Type or sniffed from a <meta charset>
+// tag, so pages served as e.g. ISO-8859-1, Windows-1251 or Shift_JIS read
+// correctly. Text content already went through JSON decoding and is returned
+// as is. Content itself is left untouched.
+//
+// Example:
+//
+//	text, err := result.UTF8Content()
+func (r *ScrapeResult) UTF8Content() (string, error) {
+	if !r.rawContent && r.Result.Format != "binary" && !strings.EqualFold(r.Result.ContentEncoding, "base64") {
+		return r.Result.Content, nil
+	}
+	raw, err := r.RawBytes()
+	if err != nil {
+		return "", err
+	}
+	enc, name, _ := charset.DetermineEncoding(raw, r.Result.ContentType)
+	decoded, err := enc.NewDecoder().Bytes(raw)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode content from %s: %w", name, err)
+	}
+	return string(decoded), nil
+}
+
+// Title returns the trimmed text of the page <title>, or "" when the page has
+// none. Returns ErrContentType if the scraped content is not HTML.
+//
+// Example:
+//
+//	title, err := result.Title()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(title)
+func (r *ScrapeResult) Title() (string, error) {
+	doc, err := r.Selector()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(doc.Find("title").First().Text()), nil
+}
+
+// FindAll returns the trimmed text of every element matching the CSS selector.
+// It returns ErrContentType when the content is not HTML; use Selector for
+// anything beyond plain text extraction.
+//
+// Example:
+//
+//	titles, err := result.FindAll(".product h2")
+func (r *ScrapeResult) FindAll(selector string) ([]string, error) {
+	doc, err := r.Selector()
+	if err != nil {
+		return nil, err
+	}
+	texts := []string{}
+	doc.Find(selector).Each(func(_ int, s *goquery.Selection) {
+		texts = append(texts, strings.TrimSpace(s.Text()))
+	})
+	return texts, nil
+}
+
+// FindAttr returns the attr attribute of every element matching the CSS
+// selector. Elements without the attribute are skipped.
+// It returns ErrContentType when the content is not HTML.
+//
+// Example:
+//
+//	images, err := result.FindAttr(".product img", "src")
+func (r *ScrapeResult) FindAttr(selector, attr string) ([]string, error) {
+	doc, err := r.Selector()
+	if err != nil {
+		return nil, err
+	}
+	values := []string{}
+	doc.Find(selector).Each(func(_ int, s *goquery.Selection) {
+		if value, ok := s.Attr(attr); ok {
+			values = append(values, value)
+		}
+	})
+	return values, nil
+}
+
+// linksOptions holds the parameters for ScrapeResult.Links.
+type linksOptions struct {
+	includeNonHTTP bool
+}
+
+// LinksOption is a function that configures ScrapeResult.Links.
+type LinksOption func(*linksOptions)
+
+// WithLinksIncludeNonHTTP sets whether javascript: and mailto: links are kept.
+// They are skipped by default since they can't be followed by a crawler.
+func WithLinksIncludeNonHTTP(include bool) LinksOption {
+	return func(o *linksOptions) {
+		o.includeNonHTTP = include
+	}
+}
+
+// Links returns the href attribute of every anchor in the scraped page.
+//
+// Relative links are resolved against the final URL of the scrape (Result.URL).
+// Links that cannot be parsed are skipped. javascript: and mailto: links are
+// skipped unless WithLinksIncludeNonHTTP(true) is passed.
+//
+// Returns ErrContentType if the scraped content is not HTML.
+//
+// Example:
+//
+//	links, err := result.Links()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, link := range links {
+//	    fmt.Println(link)
+//	}
+func (r *ScrapeResult) Links(opts ...LinksOption) ([]string, error) {
+	options := &linksOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	doc, err := r.Selector()
+	if err != nil {
+		return nil, err
+	}
+	base, err := r.baseURL()
+	if err != nil {
+		return nil, err
+	}
+
+	links := []string{}
+	doc.Find("a[href]").Each(func(_ int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		href = strings.TrimSpace(href)
+		if href == "" {
+			return
+		}
+		ref, err := url.Parse(href)
+		if err != nil {
+			return
+		}
+		switch strings.ToLower(ref.Scheme) {
+		case "javascript", "mailto":
+			if !options.includeNonHTTP {
+				return
+			}
+			links = append(links, href)
+			return
+		}
+		links = append(links, base.ResolveReference(ref).String())
+	})
+	return links, nil
+}
+
+// baseURL returns the URL relative links of the page are resolved against:
+// the final URL after redirects, falling back to the requested URL.
+func (r *ScrapeResult) baseURL() (*url.URL, error) {
+	baseURL := r.FinalURL()
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse result url %q: %w", baseURL, err)
+	}
+	return base, nil
+}
+
+// FinalURL returns the URL the scrape ended on after following upstream
+// redirects (Result.URL), falling back to the requested URL.
+func (r *ScrapeResult) FinalURL() string {
+	if r.Result.URL != "" {
+		return r.Result.URL
+	}
+	return r.Config.URL
+}
+
+// AbsoluteURL resolves href against the final URL of the scrape (Result.URL),
+// so links found on a redirected page resolve against where the page
+// actually lives. Already absolute URLs are returned unchanged.
+//
+// Example:
+//
+//	next, err := result.AbsoluteURL("/page/2")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(next) // https://example.com/page/2
+func (r *ScrapeResult) AbsoluteURL(href string) (string, error) {
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse href %q: %w", href, err)
+	}
+	if ref.IsAbs() {
+		return ref.String(), nil
+	}
+	base, err := r.baseURL()
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// contentHashOptions holds the parameters for ScrapeResult.ContentHash.
+type contentHashOptions struct {
+	normalize       bool
+	ignoreSelectors []string
+}
+
+// ContentHashOption is a function that configures ScrapeResult.ContentHash.
+type ContentHashOption func(*contentHashOptions)
+
+// WithHashNormalize sets whether whitespace is normalized before hashing:
+// runs of whitespace are collapsed and whitespace between tags is dropped,
+// so reformatted but otherwise identical pages hash the same.
+func WithHashNormalize(normalize bool) ContentHashOption {
+	return func(o *contentHashOptions) {
+		o.normalize = normalize
+	}
+}
+
+// WithHashIgnoreSelectors removes the elements matching the given CSS
+// selectors before hashing, e.g. timestamps or ad slots that change on every
+// scrape. It implies WithHashNormalize(true) and is ignored for non-HTML content.
+func WithHashIgnoreSelectors(selectors ...string) ContentHashOption {
+	return func(o *contentHashOptions) {
+		o.normalize = true
+		o.ignoreSelectors = append(o.ignoreSelectors, selectors...)
+	}
+}
+
+var (
+	whitespaceRegex        = regexp.MustCompile(`\s+`)
+	whitespaceBetweenRegex = regexp.MustCompile(`>\s+<`)
+)
+
+// ContentHash returns the hex encoded SHA-256 of the scraped content, to
+// cheaply detect changes between repeated scrapes of the same page.
+// By default the raw Content is hashed, see WithHashNormalize and
+// WithHashIgnoreSelectors to ignore volatile parts of the page.
+//
+// Example:
+//
+//	hash := result.ContentHash(scrapfly.WithHashIgnoreSelectors(".timestamp", "#ads"))
+//	if hash != previousHash {
+//	    fmt.Println("page changed")
+//	}
+func (r *ScrapeResult) ContentHash(opts ...ContentHashOption) string {
+	options := &contentHashOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	content := r.Result.Content
+	if len(options.ignoreSelectors) > 0 && strings.Contains(r.Result.ContentType, "text/html") {
+		// parse a fresh document so the cached Selector() one is left intact
+		if doc, err := goquery.NewDocumentFromReader(strings.NewReader(content)); err == nil {
+			for _, selector := range options.ignoreSelectors {
+				doc.Find(selector).Remove()
+			}
+			if html, err := doc.Html(); err == nil {
+				content = html
+			}
+		}
+	}
+	if options.normalize {
+		content = whitespaceBetweenRegex.ReplaceAllString(content, "><")
+		content = strings.TrimSpace(whitespaceRegex.ReplaceAllString(content, " "))
+	}
+
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
 // ExtractionResult represents the result of a data extraction request.
 type ExtractionResult struct {
 	// Data contains the extracted structured data.
@@ -81,6 +581,13 @@ type ExtractionResult struct {
 	ContentType string `json:"content_type"`
 	// DataQuality indicates the quality/confidence of the extraction (if available).
 	DataQuality interface{} `json:"data_quality,omitempty"`
+	// UUID identifies the extraction job. Pass it to WaitForExtraction when
+	// the extraction was submitted with a webhook.
+	UUID string `json:"uuid,omitempty"`
+	// Status is the job status of an asynchronous extraction.
+	Status string `json:"status,omitempty"`
+	// Error is set when an asynchronous extraction failed.
+	Error *APIErrorDetails `json:"error,omitempty"`
 }
 
 // errorResponse is used to unmarshal generic API errors.
@@ -149,6 +656,58 @@ type ConfigData struct {
 	UUID            string              `json:"uuid"`
 }
 
+// scrapeConfig rebuilds a ScrapeConfig from the echoed config, see
+// ScrapeResult.Refresh for what it can't restore.
+func (d ConfigData) scrapeConfig() *ScrapeConfig {
+	config := &ScrapeConfig{
+		URL:             d.URL,
+		Method:          HttpMethod(d.Method),
+		RenderJS:        d.RenderJS,
+		ASP:             d.ASP,
+		Cache:           d.Cache,
+		CacheTTL:        d.CacheTTL,
+		SSL:             d.SSL,
+		DNS:             d.DNS,
+		Debug:           d.Debug,
+		ProxyPool:       ProxyPool(d.ProxyPool),
+		Tags:            slices.Clone(d.Tags),
+		Retry:           d.Retry == nil || *d.Retry,
+		RenderingWait:   d.RenderingWait,
+		Screenshots:     maps.Clone(d.Screenshots),
+		Timeout:         d.Timeout,
+		Lang:            slices.Clone(d.Lang),
+		AutoScroll:      d.AutoScroll,
+		RenderingStage:  d.RenderingStage,
+		WaitForSelector: derefString(d.WaitForSelector),
+		JS:              derefString(d.JS),
+		Body:            derefString(d.Body),
+		Session:         derefString(d.Session),
+		CorrelationID:   derefString(d.CorrelationID),
+		BrowserBrand:    derefString(d.BrowserBrand),
+		Webhook:         derefString(d.WebhookName),
+		OS:              derefString(d.OS),
+		Country:         derefString(d.Country),
+	}
+	if config.Session != "" {
+		sticky := d.SessionStickyProxy
+		config.SessionStickyProxy = &sticky
+	}
+	for _, flag := range d.ScreenshotFlags {
+		config.ScreenshotFlags = append(config.ScreenshotFlags, ScreenshotFlag(flag))
+	}
+	if d.CostBudget != nil {
+		config.CostBudget = *d.CostBudget
+	}
+	return config
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
 // ContextData contains metadata about the scrape request execution.
 // This includes proxy information, costs, cache status, and more.
 type ContextData struct {
@@ -160,7 +719,7 @@ type ContextData struct {
 	CreatedAt         string       `json:"created_at"`
 	Debug             DebugContext `json:"debug"`
 	Env               string       `json:"env"`
-	//Fingerprint       string            `json:"fingerprint"`
+	// Fingerprint is decoded by FingerprintInfo.
 	Fingerprint      interface{}       `json:"fingerprint"`
 	Headers          map[string]string `json:"headers"`
 	IsXMLHTTPRequest bool              `json:"is_xml_http_request"`
@@ -183,6 +742,10 @@ type ContextData struct {
 // ResultData contains the scraped content and response information.
 // This is the main data from the scrape request including HTML content,
 // status codes, headers, cookies, and more.
+//
+// Binary responses (images, PDFs...) are sent base64 encoded, with Format
+// "binary" or ContentEncoding "base64": Content then holds the encoded text,
+// use ScrapeResult.RawBytes to get the decoded bytes.
 type ResultData struct {
 	BrowserData     BrowserData            `json:"browser_data"`
 	Content         string                 `json:"content"`
@@ -224,12 +787,69 @@ type CostDetail struct {
 	Description string `json:"description"`
 }
 
+// Cost categories returned by CostDetail.Category.
+const (
+	CostCategoryProxy      = "proxy"
+	CostCategoryRendering  = "rendering"
+	CostCategoryASP        = "asp"
+	CostCategoryExtraction = "extraction"
+	CostCategoryBandwidth  = "bandwidth"
+	CostCategoryOther      = "other"
+)
+
+// costCategoryRules maps cost codes to categories. Codes are matched on the
+// first keyword they contain, so codes added by Scrapfly later still land in
+// the right bucket; add a rule here when a new family of codes shows up.
+var costCategoryRules = []struct {
+	keyword  string
+	category string
+}{
+	{"BANDWIDTH", CostCategoryBandwidth},
+	{"ASP", CostCategoryASP},
+	{"EXTRACTION", CostCategoryExtraction},
+	{"EXTRACT", CostCategoryExtraction},
+	{"RENDER", CostCategoryRendering},
+	{"BROWSER", CostCategoryRendering},
+	{"JS", CostCategoryRendering},
+	{"SCREENSHOT", CostCategoryRendering},
+	{"PROXY", CostCategoryProxy},
+	{"RESIDENTIAL", CostCategoryProxy},
+	{"DATACENTER", CostCategoryProxy},
+}
+
+// Category buckets the cost item into one of the CostCategory* values based
+// on its code, returning CostCategoryOther for codes it doesn't recognize.
+func (d CostDetail) Category() string {
+	code := strings.ToUpper(d.Code)
+	for _, rule := range costCategoryRules {
+		if strings.Contains(code, rule.keyword) {
+			return rule.category
+		}
+	}
+	return CostCategoryOther
+}
+
 // CostContext contains the cost breakdown for a scrape request.
 type CostContext struct {
 	Details []CostDetail `json:"details"`
 	Total   int          `json:"total"`
 }
 
+// ByCategory sums the cost details per category, see CostDetail.Category.
+//
+// Example:
+//
+//	for category, amount := range result.Context.Cost.ByCategory() {
+//	    fmt.Println(category, amount)
+//	}
+func (c CostContext) ByCategory() map[string]int {
+	totals := make(map[string]int)
+	for _, detail := range c.Details {
+		totals[detail.Category()] += detail.Amount
+	}
+	return totals
+}
+
 // DebugContext contains URLs for debugging the request.
 type DebugContext struct {
 	ResponseURL   string      `json:"response_url"`
@@ -252,6 +872,189 @@ type ProxyContext struct {
 	Pool     string `json:"pool"`
 }
 
+// ProxyPool returns Pool as a ProxyPool, or "" when it isn't a known pool.
+func (p ProxyContext) ProxyPool() ProxyPool {
+	for _, pool := range ProxyPool("").Enum() {
+		if string(pool) == p.Pool {
+			return pool
+		}
+	}
+	return ""
+}
+
+// IsResidential reports whether the request went through a residential proxy.
+func (p ProxyContext) IsResidential() bool {
+	return strings.Contains(p.Pool, "residential")
+}
+
+// IsDatacenter reports whether the request went through a datacenter proxy.
+func (p ProxyContext) IsDatacenter() bool {
+	return strings.Contains(p.Pool, "datacenter")
+}
+
+// ASPContext describes the Anti Scraping Protection run of a scrape, see
+// ContextData.ASPInfo.
+type ASPContext struct {
+	// Status is the outcome of the bypass, e.g. "BYPASSED".
+	Status string `json:"status"`
+	// Shield is the anti-bot protection detected on the target, e.g.
+	// "cloudflare" or "datadome", or "" when none was detected.
+	Shield string `json:"shield"`
+	// Blocked reports whether the target still blocked the request.
+	Blocked bool `json:"blocked"`
+	// Fingerprint identifies the browser fingerprint used for the bypass.
+	Fingerprint string `json:"fingerprint"`
+}
+
+// ASPInfo decodes the ASP context. It returns nil, nil when Anti Scraping
+// Protection was not used for the scrape.
+//
+// Example:
+//
+//	asp, err := result.Context.ASPInfo()
+//	if err == nil && asp != nil && asp.Shield != "" {
+//	    fmt.Println("bypassed", asp.Shield, "with status", asp.Status)
+//	}
+func (c *ContextData) ASPInfo() (*ASPContext, error) {
+	switch asp := c.ASP.(type) {
+	case nil:
+		return nil, nil
+	case bool:
+		if !asp {
+			return nil, nil
+		}
+		return &ASPContext{}, nil
+	}
+	var asp ASPContext
+	if err := remarshal(c.ASP, &asp); err != nil {
+		return nil, fmt.Errorf("failed to decode asp context: %w", err)
+	}
+	return &asp, nil
+}
+
+// Fingerprint describes the TLS and HTTP fingerprint presented to the
+// target, see ContextData.FingerprintInfo.
+type Fingerprint struct {
+	// ID identifies the fingerprint profile.
+	ID string `json:"id"`
+	// JA3 is the JA3 hash of the TLS client hello.
+	JA3 string `json:"ja3"`
+	// JA4 is the JA4 TLS client fingerprint.
+	JA4 string `json:"ja4"`
+	// HTTP2 is the Akamai HTTP/2 fingerprint (SETTINGS, WINDOW_UPDATE,
+	// PRIORITY and pseudo-header order).
+	HTTP2 string `json:"http2"`
+	// UserAgent is the User-Agent sent with the fingerprint.
+	UserAgent string `json:"user_agent"`
+	// Browser is the browser the fingerprint impersonates, e.g. "chrome".
+	Browser string `json:"browser"`
+	// OS is the operating system the fingerprint impersonates.
+	OS string `json:"os"`
+}
+
+// FingerprintInfo decodes the fingerprint context. It returns nil, nil when
+// the API did not report a fingerprint. Older API versions report the
+// fingerprint as a plain identifier, which is returned as Fingerprint.ID.
+//
+// Example:
+//
+//	fp, err := result.Context.FingerprintInfo()
+//	if err == nil && fp != nil {
+//	    fmt.Println("ja3:", fp.JA3, "http2:", fp.HTTP2)
+//	}
+func (c *ContextData) FingerprintInfo() (*Fingerprint, error) {
+	switch fingerprint := c.Fingerprint.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if fingerprint == "" {
+			return nil, nil
+		}
+		return &Fingerprint{ID: fingerprint}, nil
+	}
+	var fingerprint Fingerprint
+	if err := remarshal(c.Fingerprint, &fingerprint); err != nil {
+		return nil, fmt.Errorf("failed to decode fingerprint context: %w", err)
+	}
+	return &fingerprint, nil
+}
+
+// ScheduleContext identifies the schedule that triggered a scrape, see
+// ContextData.ScheduleInfo and Client.GetSchedule.
+type ScheduleContext struct {
+	// ID is the schedule identifier.
+	ID string `json:"id"`
+	// Name is the schedule name, or its notes when it has no name.
+	Name string `json:"name"`
+	// Status is the schedule status, e.g. "ACTIVE".
+	Status string `json:"status"`
+	// NextScheduledDate is when the schedule runs next, or "" when it
+	// won't run again.
+	NextScheduledDate string `json:"next_scheduled_date"`
+}
+
+// JobContext identifies the scheduled job run a scrape belongs to, see
+// ContextData.JobInfo.
+type JobContext struct {
+	// ID is the job run identifier.
+	ID string `json:"id"`
+	// Status is the job run status, e.g. "RUNNING".
+	Status string `json:"status"`
+}
+
+// ScheduleInfo decodes the schedule context. It returns nil, nil when the
+// scrape was not part of a scheduled run. A schedule reported as a plain
+// identifier is returned as ScheduleContext.ID.
+//
+// Example:
+//
+//	schedule, err := result.Context.ScheduleInfo()
+//	if err == nil && schedule != nil {
+//	    fmt.Println("run by schedule", schedule.ID, "next run at", schedule.NextScheduledDate)
+//	}
+func (c *ContextData) ScheduleInfo() (*ScheduleContext, error) {
+	var schedule ScheduleContext
+	ok, err := decodeContextField(c.Schedule, &schedule.ID, &schedule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode schedule context: %w", err)
+	}
+	if !ok {
+		return nil, nil
+	}
+	return &schedule, nil
+}
+
+// JobInfo decodes the job context. It returns nil, nil when the scrape was
+// not part of a scheduled run. A job reported as a plain identifier is
+// returned as JobContext.ID.
+func (c *ContextData) JobInfo() (*JobContext, error) {
+	var job JobContext
+	ok, err := decodeContextField(c.Job, &job.ID, &job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode job context: %w", err)
+	}
+	if !ok {
+		return nil, nil
+	}
+	return &job, nil
+}
+
+// decodeContextField decodes a context field reported either as an object,
+// into out, or as a plain identifier, into id. It reports false when the
+// field is absent (nil, false or "").
+func decodeContextField(value interface{}, id *string, out interface{}) (bool, error) {
+	switch v := value.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return false, nil
+	case string:
+		*id = v
+		return v != "", nil
+	}
+	return true, remarshal(value, out)
+}
+
 // URIContext contains parsed URI information about the requested URL.
 type URIContext struct {
 	BaseURL    string      `json:"base_url"`
@@ -265,6 +1068,12 @@ type URIContext struct {
 }
 
 // BrowserData contains data collected from the browser during JavaScript rendering.
+//
+// The API does not capture the browser console, so there is no console log
+// field. To surface values from the page while debugging a scenario, return
+// them from ScrapeConfig.JS (or an "execute" scenario step) and read them
+// from JSEvaluationResult or ScenarioResult; this costs no extra credits
+// beyond the rendering itself.
 type BrowserData struct {
 	JSEvaluationResult *string                `json:"javascript_evaluation_result"`
 	JSScenario         interface{}            `json:"js_scenario"`
@@ -320,6 +1129,31 @@ type IFrame struct {
 	Content string     `json:"content"`
 }
 
+// IFrameByURL returns the first captured iframe whose URL contains substr,
+// for instance a maps or widget host. Iframes are captured by the API when
+// the page is rendered with ScrapeConfig.RenderJS; there is no option to
+// select them up front. It returns an ErrDataNotRequested error when no
+// iframe was captured and an ErrIFrameNotFound error when none matches.
+//
+// Example:
+//
+//	frame, err := result.IFrameByURL("google.com/maps")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(frame.URL, len(frame.Content))
+func (r *ScrapeResult) IFrameByURL(substr string) (*IFrame, error) {
+	if len(r.Result.IFrames) == 0 {
+		return nil, fmt.Errorf("%w: no iframes captured, enable ScrapeConfig.RenderJS", ErrDataNotRequested)
+	}
+	for i := range r.Result.IFrames {
+		if strings.Contains(r.Result.IFrames[i].URL, substr) {
+			return &r.Result.IFrames[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no iframe url contains %q", ErrIFrameNotFound, substr)
+}
+
 // Screenshot represents a screenshot captured during rendering.
 type Screenshot struct {
 	// CSSSelector is the CSS selector of the element to capture. If Format == fullpage, this will be nil
@@ -379,7 +1213,8 @@ func (a *Attachment) Data() ([]byte, error) {
 //   - savePath: Optional directory path where to save the file (defaults to current directory)
 //     (if savePath does not exists, it will be created in a best effort basis)
 //
-// it is named as the filename of the attachment
+// it is named as the filename of the attachment, without any directory
+// part since the name comes from the target website
 // Returns the full path to the saved file.
 //
 // Example:
@@ -390,22 +1225,29 @@ func (a *Attachment) Data() ([]byte, error) {
 //	}
 //	fmt.Printf("Attachment %s saved to: %s\n", a.Filename, filePath)
 func (a *Attachment) Save(savePath ...string) (string, error) {
+	dir := "."
+	if len(savePath) > 0 {
+		dir = savePath[0]
+	}
+	return a.save(dir, a.Filename)
+}
+
+// save writes the attachment content to dir as name, fetching it first if
+// needed.
+func (a *Attachment) save(dir, name string) (string, error) {
 	if a.data == nil {
-		_, err := a.Data()
-		if err != nil {
+		if _, err := a.Data(); err != nil {
 			return "", err
 		}
 	}
-	dir := "."
-	if len(savePath) > 0 {
-		dir = savePath[0]
+	filePath, err := safeJoin(dir, name)
+	if err != nil {
+		return "", err
 	}
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return "", err
 	}
-	filePath := filepath.Join(dir, fmt.Sprintf("%s", a.Filename))
-	err := os.WriteFile(filePath, a.data, 0644)
-	return filePath, err
+	return filePath, os.WriteFile(filePath, a.data, 0644)
 }
 
 // Save saves a scraped screenshot result to disk.
@@ -435,12 +1277,14 @@ func (s *Screenshot) Save(savePath ...string) (string, error) {
 	if len(savePath) > 0 {
 		dir = savePath[0]
 	}
+	filePath, err := safeJoin(dir, fmt.Sprintf("%s.%s", s.Name, s.Extension))
+	if err != nil {
+		return "", err
+	}
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return "", err
 	}
-	filePath := filepath.Join(dir, fmt.Sprintf("%s.%s", s.Name, s.Extension))
-	err := os.WriteFile(filePath, s.image, 0644)
-	return filePath, err
+	return filePath, os.WriteFile(filePath, s.image, 0644)
 }
 
 // SaveScreenshots is a shortcut to save all screenshots to disk
@@ -490,9 +1334,65 @@ func (r *ScrapeResult) SaveScreenshots(savePath ...string) ([]string, error) {
 //		fmt.Printf("Attachment saved to: %s\n", path)
 //	}
 func (r *ScrapeResult) SaveAttachments(savePath ...string) ([]string, error) {
+	dir := "."
+	if len(savePath) > 0 {
+		dir = savePath[0]
+	}
+	return r.SaveAttachmentsTo(dir)
+}
+
+// SaveAttachmentsOption is a function that configures
+// ScrapeResult.SaveAttachmentsTo.
+type SaveAttachmentsOption func(*saveAttachmentsOptions)
+
+type saveAttachmentsOptions struct {
+	suggestedFilename bool
+}
+
+// WithSuggestedFilename names the files after the attachment
+// SuggestedFilename, the name the target website suggested for the
+// download, instead of Filename. Filename is still used for attachments
+// without a suggested name.
+func WithSuggestedFilename() SaveAttachmentsOption {
+	return func(o *saveAttachmentsOptions) {
+		o.suggestedFilename = true
+	}
+}
+
+// SaveAttachmentsTo saves all attachments to dir, which is created if
+// needed, and returns the paths of the saved files in attachment order.
+//
+// Only the last element of each filename is kept. Attachments sharing it
+// don't overwrite each other: the later ones get the attachment ID, or a
+// counter, appended to their name (report.pdf, report-<id>.pdf,
+// report-2.pdf...).
+//
+// Example:
+//
+//	paths, err := r.SaveAttachmentsTo("./attachments", scrapfly.WithSuggestedFilename())
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (r *ScrapeResult) SaveAttachmentsTo(dir string, opts ...SaveAttachmentsOption) ([]string, error) {
+	var options saveAttachmentsOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
 	paths := []string{}
-	for _, attachment := range r.Result.BrowserData.Attachments {
-		filePath, err := attachment.Save(savePath...)
+	used := map[string]bool{}
+	for i := range r.Result.BrowserData.Attachments {
+		attachment := &r.Result.BrowserData.Attachments[i]
+		name := attachment.Filename
+		if options.suggestedFilename && attachment.SuggestedFilename != "" {
+			name = attachment.SuggestedFilename
+		}
+		name, err := safeFilename(name)
+		if err != nil {
+			return nil, err
+		}
+		name = uniqueFilename(name, attachment.ID, used)
+		used[name] = true
+		filePath, err := attachment.save(dir, name)
 		if err != nil {
 			return nil, err
 		}
@@ -500,3 +1400,23 @@ func (r *ScrapeResult) SaveAttachments(savePath ...string) ([]string, error) {
 	}
 	return paths, nil
 }
+
+// uniqueFilename returns name, or name suffixed with id or a counter before
+// its extension, whichever is not in used yet.
+func uniqueFilename(name, id string, used map[string]bool) string {
+	if !used[name] {
+		return name
+	}
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	if id != "" {
+		if candidate := base + "-" + id + ext; !used[candidate] {
+			return candidate
+		}
+	}
+	for n := 2; ; n++ {
+		if candidate := fmt.Sprintf("%s-%d%s", base, n, ext); !used[candidate] {
+			return candidate
+		}
+	}
+}
@@ -0,0 +1,761 @@
+package scrapfly
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func htmlResult(pageURL, content string) *ScrapeResult {
+	return &ScrapeResult{
+		Result: ResultData{
+			URL:         pageURL,
+			ContentType: "text/html; charset=utf-8",
+			Content:     content,
+		},
+	}
+}
+
+const linksTestPage = `<html><body>
+<a href="/about">About</a>
+<a href="contact.html">Contact</a>
+<a href="https://other.example.org/x?y=1">External</a>
+<a href="mailto:hello@example.com">Mail</a>
+<a href="javascript:void(0)">JS</a>
+<a href="">Empty</a>
+<a>No href</a>
+</body></html>`
+
+func TestScrapeResult_LinksResolvesRelative(t *testing.T) {
+	r := htmlResult("https://example.com/docs/index.html", linksTestPage)
+	links, err := r.Links()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"https://example.com/about",
+		"https://example.com/docs/contact.html",
+		"https://other.example.org/x?y=1",
+	}
+	if !reflect.DeepEqual(links, want) {
+		t.Errorf("links = %v, want %v", links, want)
+	}
+}
+
+func TestScrapeResult_LinksIncludeNonHTTP(t *testing.T) {
+	r := htmlResult("https://example.com/", linksTestPage)
+	links, err := r.Links(WithLinksIncludeNonHTTP(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 5 {
+		t.Fatalf("expected 5 links, got %d: %v", len(links), links)
+	}
+	if links[3] != "mailto:hello@example.com" || links[4] != "javascript:void(0)" {
+		t.Errorf("non-http links not kept verbatim: %v", links[3:])
+	}
+}
+
+func TestScrapeResult_LinksNonHTML(t *testing.T) {
+	r := &ScrapeResult{Result: ResultData{ContentType: "application/json", Content: "{}"}}
+	if _, err := r.Links(); !errors.Is(err, ErrContentType) {
+		t.Errorf("expected ErrContentType, got %v", err)
+	}
+}
+
+func TestScrapeResult_AbsoluteURL(t *testing.T) {
+	r := htmlResult("https://example.com/final/page", "")
+	r.Config.URL = "https://example.com/redirecting"
+
+	cases := map[string]string{
+		"next":                       "https://example.com/final/next",
+		"/root":                      "https://example.com/root",
+		"?page=2":                    "https://example.com/final/page?page=2",
+		"//cdn.example.com/a.js":     "https://cdn.example.com/a.js",
+		"https://other.example.org/": "https://other.example.org/",
+	}
+	for href, want := range cases {
+		got, err := r.AbsoluteURL(href)
+		if err != nil {
+			t.Errorf("AbsoluteURL(%q): %v", href, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("AbsoluteURL(%q) = %q, want %q", href, got, want)
+		}
+	}
+
+	if _, err := r.AbsoluteURL("http://[::1"); err == nil {
+		t.Error("expected error for malformed href")
+	}
+}
+
+func TestScrapeResult_FindAllAndFindAttr(t *testing.T) {
+	r := htmlResult("https://example.com", `<ul>
+<li class="item"><a href="/a"> First </a></li>
+<li class="item"><a href="/b">Second</a></li>
+<li class="item"><a>Third</a></li>
+</ul>`)
+	texts, err := r.FindAll(".item a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"First", "Second", "Third"}; !reflect.DeepEqual(texts, want) {
+		t.Errorf("FindAll = %v, want %v", texts, want)
+	}
+	hrefs, err := r.FindAttr(".item a", "href")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/a", "/b"}; !reflect.DeepEqual(hrefs, want) {
+		t.Errorf("FindAttr = %v, want %v", hrefs, want)
+	}
+}
+
+func TestScrapeResult_FindAllNonHTML(t *testing.T) {
+	r := &ScrapeResult{Result: ResultData{ContentType: "application/json", Content: `{}`}}
+	if _, err := r.FindAll("a"); !errors.Is(err, ErrContentType) {
+		t.Errorf("FindAll: expected ErrContentType, got %v", err)
+	}
+	if _, err := r.FindAttr("a", "href"); !errors.Is(err, ErrContentType) {
+		t.Errorf("FindAttr: expected ErrContentType, got %v", err)
+	}
+}
+
+// "Привет" encoded as Windows-1251.
+const cp1251Privet = "\xcf\xf0\xe8\xe2\xe5\xf2"
+
+// binaryScrapeResult decodes an API payload carrying content as base64,
+// the way the API returns pages it could not decode as text.
+func binaryScrapeResult(t *testing.T, contentType, content string) *ScrapeResult {
+	t.Helper()
+	payload, _ := json.Marshal(map[string]interface{}{
+		"result": map[string]interface{}{
+			"success": true, "status": "DONE", "status_code": 200, "format": "binary",
+			"content_type": contentType, "content": base64.StdEncoding.EncodeToString([]byte(content)),
+		},
+	})
+	var result ScrapeResult
+	if err := json.Unmarshal(payload, &result); err != nil {
+		t.Fatal(err)
+	}
+	return &result
+}
+
+func TestScrapeResult_UTF8ContentFromHeader(t *testing.T) {
+	r := binaryScrapeResult(t, "text/html; charset=windows-1251", "<html><body>"+cp1251Privet+"</body></html>")
+	content := r.Result.Content
+	text, err := r.UTF8Content()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "<html><body>Привет</body></html>" {
+		t.Errorf("UTF8Content = %q", text)
+	}
+	if r.Result.Content != content {
+		t.Error("Content must be left untouched")
+	}
+}
+
+func TestScrapeResult_UTF8ContentFromMeta(t *testing.T) {
+	r := binaryScrapeResult(t, "text/html", `<html><head><meta charset="windows-1251"></head><body>`+cp1251Privet+`</body></html>`)
+	text, err := r.UTF8Content()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text, "Привет") {
+		t.Errorf("UTF8Content = %q", text)
+	}
+}
+
+func TestScrapeResult_UTF8ContentText(t *testing.T) {
+	var r ScrapeResult
+	payload := `{"result": {"success": true, "status": "DONE", "status_code": 200, "format": "text", "content_type": "text/html; charset=windows-1251", "content": "Привет"}}`
+	if err := json.Unmarshal([]byte(payload), &r); err != nil {
+		t.Fatal(err)
+	}
+	if text, _ := r.UTF8Content(); text != "Привет" {
+		t.Errorf("text content should be returned as is, got %q", text)
+	}
+}
+
+func TestScrapeResult_CacheState(t *testing.T) {
+	cases := []struct {
+		state string
+		hit   bool
+	}{
+		{"HIT", true},
+		{"hit", true},
+		{"MISS", false},
+		{"", false},
+	}
+	for _, tc := range cases {
+		r := &ScrapeResult{Context: ContextData{Cache: CacheContext{State: tc.state}}}
+		if r.CacheState() != tc.state {
+			t.Errorf("CacheState() = %q, want %q", r.CacheState(), tc.state)
+		}
+		if r.IsFromCache() != tc.hit {
+			t.Errorf("IsFromCache() for %q = %v, want %v", tc.state, r.IsFromCache(), tc.hit)
+		}
+	}
+}
+
+// 1x1 transparent PNG.
+const pixelPNGBase64 = "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mNkYAAAAAYAAjCB0C8AAAAASUVORK5CYII="
+
+func TestScrapeResult_RawBytesBase64(t *testing.T) {
+	r := &ScrapeResult{Result: ResultData{Format: "binary", ContentType: "image/png", Content: pixelPNGBase64}}
+	data, err := r.RawBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "\x89PNG\r\n\x1a\n") {
+		t.Errorf("data is not a PNG: %q", data[:8])
+	}
+
+	pdf := base64.StdEncoding.EncodeToString([]byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n"))
+	r = &ScrapeResult{Result: ResultData{Format: "raw", ContentEncoding: "base64", ContentType: "application/pdf", Content: pdf}}
+	data, err = r.RawBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "%PDF-1.7\n%\xe2\xe3\xcf\xd3\n" {
+		t.Errorf("data = %q", data)
+	}
+
+	r = &ScrapeResult{Result: ResultData{Format: "binary", Content: "not base64!"}}
+	if _, err := r.RawBytes(); err == nil {
+		t.Error("expected a decoding error")
+	}
+}
+
+func TestScrapeResult_RawBytesText(t *testing.T) {
+	r := &ScrapeResult{Result: ResultData{Format: "raw", Content: "<html></html>"}}
+	data, err := r.RawBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "<html></html>" {
+		t.Errorf("data = %q", data)
+	}
+}
+
+func TestScrapeResult_ContentHash(t *testing.T) {
+	a := htmlResult("https://example.com", "<html><body><p>Hello</p></body></html>")
+	b := htmlResult("https://example.com", "<html><body><p>Hello</p></body></html>")
+	c := htmlResult("https://example.com", "<html><body><p>Bye</p></body></html>")
+	if a.ContentHash() != b.ContentHash() {
+		t.Error("identical content should hash the same")
+	}
+	if a.ContentHash() == c.ContentHash() {
+		t.Error("different content should hash differently")
+	}
+	if len(a.ContentHash()) != 64 {
+		t.Errorf("hash = %q, want hex encoded SHA-256", a.ContentHash())
+	}
+}
+
+func TestScrapeResult_ContentHashNormalizesWhitespace(t *testing.T) {
+	compact := htmlResult("https://example.com", "<html><body><p>Hello world</p><p>Bye</p></body></html>")
+	pretty := htmlResult("https://example.com", "<html>\n  <body>\n    <p>Hello   world</p>\n\t<p>Bye</p>\n  </body>\n</html>\n")
+	if compact.ContentHash() == pretty.ContentHash() {
+		t.Error("raw hash should see whitespace differences")
+	}
+	if compact.ContentHash(WithHashNormalize(true)) != pretty.ContentHash(WithHashNormalize(true)) {
+		t.Error("normalized hashes should ignore whitespace differences")
+	}
+}
+
+func TestScrapeResult_ContentHashIgnoreSelectors(t *testing.T) {
+	first := htmlResult("https://example.com", `<html><body><p>Price: 10</p><span class="ts">12:00:01</span></body></html>`)
+	second := htmlResult("https://example.com", "<html><body>\n<p>Price: 10</p>\n<span class=\"ts\">12:05:42</span>\n</body></html>")
+	if first.ContentHash(WithHashIgnoreSelectors(".ts")) != second.ContentHash(WithHashIgnoreSelectors(".ts")) {
+		t.Error("hashes should ignore elements matching the selectors")
+	}
+
+	changed := htmlResult("https://example.com", `<html><body><p>Price: 12</p><span class="ts">12:00:01</span></body></html>`)
+	if first.ContentHash(WithHashIgnoreSelectors(".ts")) == changed.ContentHash(WithHashIgnoreSelectors(".ts")) {
+		t.Error("hashes should still see changes outside the ignored elements")
+	}
+
+	doc, err := first.Selector()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Find(".ts").Length() != 1 {
+		t.Error("ContentHash modified the cached Selector document")
+	}
+}
+
+func TestScrapeResult_Title(t *testing.T) {
+	r := htmlResult("https://example.com", "<html><head><title>\n  Example Domain \n</title></head><body><svg><title>icon</title></svg></body></html>")
+	title, err := r.Title()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title != "Example Domain" {
+		t.Errorf("title = %q", title)
+	}
+
+	title, err = htmlResult("https://example.com", "<html><body>no title</body></html>").Title()
+	if err != nil || title != "" {
+		t.Errorf("title = %q, err = %v", title, err)
+	}
+
+	r = &ScrapeResult{Result: ResultData{ContentType: "application/json", Content: `{"title": "x"}`}}
+	if _, err := r.Title(); !errors.Is(err, ErrContentType) {
+		t.Errorf("expected ErrContentType, got %v", err)
+	}
+}
+
+func TestScrapeResult_SetContentAfterJSONRoundTrip(t *testing.T) {
+	original := htmlResult("https://example.com/blog/", `<html><head><title>Blog</title></head><body><a href="post-1">Post</a></body></html>`)
+	if _, err := original.Selector(); err != nil {
+		t.Fatal(err)
+	}
+	html := original.Result.Content
+
+	// Persist the metadata and the HTML separately.
+	original.Result.Content = ""
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored ScrapeResult
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+	restored.SetContent(html)
+
+	title, err := restored.Title()
+	if err != nil || title != "Blog" {
+		t.Errorf("title = %q, err = %v", title, err)
+	}
+	links, err := restored.Links()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(links, []string{"https://example.com/blog/post-1"}) {
+		t.Errorf("links = %v", links)
+	}
+
+	// The cached document is replaced along with the content.
+	restored.SetContent(`<html><head><title>Updated</title></head></html>`)
+	if title, _ := restored.Title(); title != "Updated" {
+		t.Errorf("title after SetContent = %q, want Updated", title)
+	}
+}
+
+func TestScrapeResult_SetContentDefaultsToHTML(t *testing.T) {
+	var r ScrapeResult
+	r.SetContent(`<title>Stored</title>`)
+	if title, err := r.Title(); err != nil || title != "Stored" {
+		t.Errorf("title = %q, err = %v", title, err)
+	}
+}
+
+func TestContextData_ASPInfo(t *testing.T) {
+	var result ScrapeResult
+	body := `{"context": {"asp": {"status": "BYPASSED", "shield": "cloudflare", "blocked": false, "fingerprint": "chrome-124-win"}}}`
+	if err := json.Unmarshal([]byte(body), &result); err != nil {
+		t.Fatal(err)
+	}
+	asp, err := result.Context.ASPInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &ASPContext{Status: "BYPASSED", Shield: "cloudflare", Fingerprint: "chrome-124-win"}
+	if !reflect.DeepEqual(asp, want) {
+		t.Errorf("asp = %+v, want %+v", asp, want)
+	}
+
+	for _, raw := range []string{`{"context": {}}`, `{"context": {"asp": null}}`, `{"context": {"asp": false}}`} {
+		var result ScrapeResult
+		if err := json.Unmarshal([]byte(raw), &result); err != nil {
+			t.Fatal(err)
+		}
+		if asp, err := result.Context.ASPInfo(); asp != nil || err != nil {
+			t.Errorf("%s: asp = %+v, err = %v, want nil, nil", raw, asp, err)
+		}
+	}
+
+	result.Context.ASP = map[string]interface{}{"blocked": "yes"}
+	if _, err := result.Context.ASPInfo(); err == nil {
+		t.Error("expected a decoding error")
+	}
+}
+
+func TestContextData_FingerprintInfo(t *testing.T) {
+	var result ScrapeResult
+	body := `{"context": {"fingerprint": {"ja3": "771,4865-4866", "http2": "1:65536;4:6291456|15663105|0|m,a,s,p", "browser": "chrome", "os": "windows"}}}`
+	if err := json.Unmarshal([]byte(body), &result); err != nil {
+		t.Fatal(err)
+	}
+	fp, err := result.Context.FingerprintInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Fingerprint{JA3: "771,4865-4866", HTTP2: "1:65536;4:6291456|15663105|0|m,a,s,p", Browser: "chrome", OS: "windows"}
+	if !reflect.DeepEqual(fp, want) {
+		t.Errorf("fingerprint = %+v, want %+v", fp, want)
+	}
+
+	result.Context.Fingerprint = nil
+	if fp, err := result.Context.FingerprintInfo(); fp != nil || err != nil {
+		t.Errorf("absent fingerprint: fp = %+v, err = %v", fp, err)
+	}
+	result.Context.Fingerprint = "chrome-124"
+	if fp, err := result.Context.FingerprintInfo(); err != nil || fp.ID != "chrome-124" {
+		t.Errorf("string fingerprint: fp = %+v, err = %v", fp, err)
+	}
+	result.Context.Fingerprint = []interface{}{"unexpected"}
+	if _, err := result.Context.FingerprintInfo(); err == nil {
+		t.Error("expected a decoding error")
+	}
+}
+
+func TestContextData_ScheduleAndJobInfo(t *testing.T) {
+	var result ScrapeResult
+	body := `{"context": {"schedule": {"id": "sch-1", "name": "daily prices", "status": "ACTIVE", "next_scheduled_date": "2026-10-15T00:00:00Z"}, "job": {"id": "job-9", "status": "RUNNING"}}}`
+	if err := json.Unmarshal([]byte(body), &result); err != nil {
+		t.Fatal(err)
+	}
+	schedule, err := result.Context.ScheduleInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&ScheduleContext{ID: "sch-1", Name: "daily prices", Status: "ACTIVE", NextScheduledDate: "2026-10-15T00:00:00Z"}); !reflect.DeepEqual(schedule, want) {
+		t.Errorf("schedule = %+v, want %+v", schedule, want)
+	}
+	job, err := result.Context.JobInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&JobContext{ID: "job-9", Status: "RUNNING"}); !reflect.DeepEqual(job, want) {
+		t.Errorf("job = %+v, want %+v", job, want)
+	}
+
+	for _, absent := range []interface{}{nil, false, ""} {
+		result.Context.Schedule, result.Context.Job = absent, absent
+		if schedule, err := result.Context.ScheduleInfo(); schedule != nil || err != nil {
+			t.Errorf("absent schedule %#v: schedule = %+v, err = %v", absent, schedule, err)
+		}
+		if job, err := result.Context.JobInfo(); job != nil || err != nil {
+			t.Errorf("absent job %#v: job = %+v, err = %v", absent, job, err)
+		}
+	}
+	result.Context.Schedule = "sch-2"
+	if schedule, err := result.Context.ScheduleInfo(); err != nil || schedule.ID != "sch-2" {
+		t.Errorf("string schedule: schedule = %+v, err = %v", schedule, err)
+	}
+	result.Context.Job = []interface{}{"unexpected"}
+	if _, err := result.Context.JobInfo(); err == nil {
+		t.Error("expected a decoding error")
+	}
+}
+
+func attachmentServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, strings.TrimPrefix(r.URL.Path, "/"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestScrapeResult_SaveAttachmentsCollidingFilenames(t *testing.T) {
+	server := attachmentServer(t)
+	r := &ScrapeResult{}
+	r.Result.BrowserData.Attachments = []Attachment{
+		{ID: "a1", Filename: "report.pdf", Content: server.URL + "/first"},
+		{ID: "a2", Filename: "report.pdf", Content: server.URL + "/second"},
+		{ID: "a2", Filename: "report.pdf", Content: server.URL + "/third"},
+		{Filename: "report-a1.pdf", Content: server.URL + "/fourth"},
+	}
+	dir := t.TempDir()
+	paths, err := r.SaveAttachments(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"report.pdf":    "first",
+		"report-a2.pdf": "second",
+		"report-2.pdf":  "third",
+		"report-a1.pdf": "fourth",
+	}
+	if len(paths) != len(want) {
+		t.Fatalf("paths = %v", paths)
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(data) != content {
+			t.Errorf("%s = %q, err = %v, want %q", name, data, err, content)
+		}
+	}
+}
+
+func TestScrapeResult_SaveAttachmentsSameBaseName(t *testing.T) {
+	server := attachmentServer(t)
+	r := &ScrapeResult{}
+	r.Result.BrowserData.Attachments = []Attachment{
+		{ID: "a1", Filename: "a/report.pdf", Content: server.URL + "/first"},
+		{ID: "a2", Filename: "report.pdf", Content: server.URL + "/second"},
+	}
+	dir := t.TempDir()
+	paths, err := r.SaveAttachments(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(dir, "report.pdf"), filepath.Join(dir, "report-a2.pdf")}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+	for i, content := range []string{"first", "second"} {
+		if data, err := os.ReadFile(paths[i]); err != nil || string(data) != content {
+			t.Errorf("%s = %q, err = %v, want %q", paths[i], data, err, content)
+		}
+	}
+}
+
+func TestPDFResult_SaveHostileName(t *testing.T) {
+	dir := t.TempDir()
+	filePath, err := (&PDFResult{Data: []byte("%PDF")}).Save("../../invoice", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filePath != filepath.Join(dir, "invoice.pdf") {
+		t.Errorf("saved to %s, want inside %s", filePath, dir)
+	}
+}
+
+func TestScrapeResult_SaveAttachmentsSuggestedFilename(t *testing.T) {
+	server := attachmentServer(t)
+	r := &ScrapeResult{}
+	r.Result.BrowserData.Attachments = []Attachment{
+		{ID: "a1", Filename: "download", SuggestedFilename: "invoice-2024.pdf", Content: server.URL + "/invoice"},
+		{ID: "a2", Filename: "notes.txt", Content: server.URL + "/notes"},
+	}
+	dir := t.TempDir()
+	paths, err := r.SaveAttachmentsTo(dir, WithSuggestedFilename())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(dir, "invoice-2024.pdf"), filepath.Join(dir, "notes.txt")}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+}
+
+func TestAttachment_SaveHostileFilename(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "attachments")
+	for _, name := range []string{"../../etc/cron.d/x", `..\..\x`, "/x"} {
+		a := &Attachment{Filename: name, data: []byte("payload")}
+		filePath, err := a.Save(dir)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if filePath != filepath.Join(dir, "x") {
+			t.Errorf("%s saved to %s, want inside %s", name, filePath, dir)
+		}
+	}
+	entries, _ := os.ReadDir(root)
+	if len(entries) != 1 || entries[0].Name() != "attachments" {
+		t.Errorf("files escaped the save directory: %v", entries)
+	}
+
+	for _, name := range []string{"..", "", "a/.."} {
+		if _, err := (&Attachment{Filename: name, data: []byte("x")}).Save(dir); err == nil {
+			t.Errorf("%q: expected an error", name)
+		}
+	}
+}
+
+func TestScreenshot_SaveHostileName(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "shots")
+	s := &Screenshot{Name: "../../evil", Extension: "png", image: []byte("png")}
+	filePath, err := s.Save(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filePath != filepath.Join(dir, "evil.png") {
+		t.Errorf("saved to %s, want inside %s", filePath, dir)
+	}
+}
+
+func TestCostContext_ByCategory(t *testing.T) {
+	cost := CostContext{Total: 57, Details: []CostDetail{
+		{Amount: 1, Code: "API_CALL"},
+		{Amount: 25, Code: "PROXY_RESIDENTIAL"},
+		{Amount: 5, Code: "JS_RENDERING"},
+		{Amount: 20, Code: "ASP"},
+		{Amount: 3, Code: "BANDWIDTH_RESIDENTIAL"},
+		{Amount: 2, Code: "extraction_template"},
+		{Amount: 1, Code: "SCREENSHOT"},
+	}}
+	want := map[string]int{
+		CostCategoryOther:      1,
+		CostCategoryProxy:      25,
+		CostCategoryRendering:  6,
+		CostCategoryASP:        20,
+		CostCategoryBandwidth:  3,
+		CostCategoryExtraction: 2,
+	}
+	if got := cost.ByCategory(); !maps.Equal(got, want) {
+		t.Errorf("ByCategory() = %v, want %v", got, want)
+	}
+}
+
+func TestScrapeResult_JSONRoundTrip(t *testing.T) {
+	result := htmlResult("https://web-scraping.dev/product/1", `<html><head><title>Box of Chocolate Candy</title></head><body></body></html>`)
+	result.UUID = "01HX"
+	result.Context.Cost = CostContext{Total: 6, Details: []CostDetail{{Amount: 1, Code: "API_CALL"}, {Amount: 5, Code: "JS_RENDERING"}}}
+	result.Result.ResponseHeaders = map[string]interface{}{"set-cookie": []interface{}{"a=1", "b=2"}}
+	result.Result.ExtractedData = &ExtractionResult{
+		ContentType: "application/json",
+		Data: map[string]interface{}{
+			"name":             "Box of Chocolate Candy",
+			"brand":            "ChocoDelight",
+			"aggregate_rating": map[string]interface{}{"best_rating": float64(5), "rating_value": 4.7, "review_count": float64(10)},
+			"offers":           []interface{}{map[string]interface{}{"currency": "USD", "price": 9.99}},
+		},
+	}
+	if title, err := result.Title(); err != nil || title != "Box of Chocolate Candy" {
+		t.Fatalf("Title() = %q, %v", title, err)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ScrapeResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.Result, result.Result) || !reflect.DeepEqual(decoded.Context, result.Context) || decoded.UUID != result.UUID {
+		t.Errorf("round-trip mismatch:\n got %+v\nwant %+v", decoded.Result, result.Result)
+	}
+	if title, err := decoded.Title(); err != nil || title != "Box of Chocolate Candy" {
+		t.Errorf("decoded Title() = %q, %v", title, err)
+	}
+
+	// Unmarshaling over a used result drops its cached document.
+	if err := json.Unmarshal([]byte(`{"result": {"content_type": "text/html", "content": "<title>Other</title>"}}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if title, _ := decoded.Title(); title != "Other" {
+		t.Errorf("Title() after re-decoding = %q, want Other", title)
+	}
+}
+
+func TestScrapeResult_JSONRoundTripRawContent(t *testing.T) {
+	raw := string([]byte{0x89, 'P', 'N', 'G', 0xff, 0x00})
+	result := &ScrapeResult{Result: ResultData{Content: raw, Format: "binary", ContentType: "image/png"}, rawContent: true}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ScrapeResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	got, err := decoded.RawBytes()
+	if err != nil || string(got) != raw {
+		t.Errorf("RawBytes() = %q, %v, want %q", got, err, raw)
+	}
+}
+
+func TestScrapeResult_FinalURL(t *testing.T) {
+	var result ScrapeResult
+	if err := json.Unmarshal([]byte(`{"config": {"url": "http://example.com"}, "result": {"url": "https://www.example.com/home"}}`), &result); err != nil {
+		t.Fatal(err)
+	}
+	if got := result.FinalURL(); got != "https://www.example.com/home" {
+		t.Errorf("FinalURL() = %q", got)
+	}
+
+	direct := &ScrapeResult{Config: ConfigData{URL: "https://example.com"}}
+	if got := direct.FinalURL(); got != "https://example.com" {
+		t.Errorf("FinalURL() = %q, want the requested URL", got)
+	}
+}
+
+func TestScrapeResult_IFrameByURL(t *testing.T) {
+	result := &ScrapeResult{Result: ResultData{IFrames: []IFrame{
+		{URL: "https://ads.example.com/banner", Content: "ad"},
+		{URL: "https://www.google.com/maps/embed?pb=1", Content: "map"},
+	}}}
+	frame, err := result.IFrameByURL("google.com/maps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if frame.Content != "map" {
+		t.Errorf("frame = %+v", frame)
+	}
+	if _, err := result.IFrameByURL("youtube.com"); !errors.Is(err, ErrIFrameNotFound) {
+		t.Errorf("expected ErrIFrameNotFound, got %v", err)
+	}
+	if _, err := (&ScrapeResult{}).IFrameByURL("maps"); !errors.Is(err, ErrDataNotRequested) {
+		t.Errorf("expected ErrDataNotRequested, got %v", err)
+	}
+}
+
+func TestScrapeResult_Refresh(t *testing.T) {
+	var queries []url.Values
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(scrapeDoneResponse))
+	})
+
+	config := &ScrapeConfig{URL: "https://web-scraping.dev/product/1", RenderJS: true, Format: FormatMarkdown, Cache: true}
+	result, err := client.Scrape(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.URL = "https://web-scraping.dev/product/2"
+	if _, err := result.Refresh(nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("requests = %d, want 2", len(queries))
+	}
+	refresh := queries[1]
+	if refresh.Get("url") != "https://web-scraping.dev/product/1" || refresh.Get("render_js") != "true" || refresh.Get("format") != "markdown" {
+		t.Errorf("refresh query = %v", refresh)
+	}
+	if queries[0].Get("cache_clear") != "" || refresh.Get("cache") != "true" || refresh.Get("cache_clear") != "true" {
+		t.Errorf("cache = %q, cache_clear = %q", refresh.Get("cache"), refresh.Get("cache_clear"))
+	}
+
+	// A result decoded from JSON is refreshed with its echoed config.
+	var decoded ScrapeResult
+	if err := json.Unmarshal([]byte(`{"config": {"url": "https://web-scraping.dev/products", "method": "GET", "country": "us", "render_js": true, "asp": true, "tags": ["monitor"], "session": "s1", "session_sticky_proxy": true, "wait_for_selector": ".product", "cost_budget": 30}}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decoded.Refresh(nil); err == nil {
+		t.Error("expected an error refreshing without a client")
+	}
+	if _, err := decoded.Refresh(client); err != nil {
+		t.Fatal(err)
+	}
+	refresh = queries[2]
+	for key, want := range map[string]string{
+		"url": "https://web-scraping.dev/products", "country": "us", "render_js": "true", "asp": "true", "tags": "monitor",
+		"session": "s1", "session_sticky_proxy": "true", "wait_for_selector": ".product", "cost_budget": "30", "cache_clear": "true",
+	} {
+		if got := refresh.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
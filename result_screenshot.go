@@ -27,12 +27,31 @@ type ScreenshotMetadata struct {
 	UpstreamURL string
 }
 
+// sniffedExtensions maps the content types detected from the image bytes
+// to file extensions, for responses without a usable Content-Type.
+var sniffedExtensions = map[string]string{
+	"image/jpeg":      "jpg",
+	"image/png":       "png",
+	"image/webp":      "webp",
+	"image/gif":       "gif",
+	"application/pdf": "pdf",
+}
+
+// isGenericContentType reports whether contentType says nothing about the
+// image format.
+func isGenericContentType(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	return mediaType == "application/octet-stream" || mediaType == "binary/octet-stream"
+}
+
 // newScreenshotResult creates a ScreenshotResult from an HTTP response.
 func newScreenshotResult(resp *http.Response, data []byte) (*ScreenshotResult, error) {
 	contentType := resp.Header.Get("Content-Type")
 	ext := "bin"
-	if parts := strings.Split(contentType, "/"); len(parts) == 2 {
+	if parts := strings.Split(contentType, "/"); len(parts) == 2 && !isGenericContentType(contentType) {
 		ext = strings.Split(parts[1], ";")[0]
+	} else if sniffed, ok := sniffedExtensions[strings.Split(http.DetectContentType(data), ";")[0]]; ok {
+		ext = sniffed
 	}
 
 	statusCodeStr := resp.Header.Get("x-scrapfly-upstream-http-code")
@@ -78,3 +97,44 @@ func (s *ScreenshotResult) Save(name string, savePath ...string) (string, error)
 	err := os.WriteFile(filePath, s.Image, 0644)
 	return filePath, err
 }
+
+// PDFResult represents a PDF rendered by Client.RenderPDF.
+type PDFResult struct {
+	// Data contains the raw PDF bytes.
+	Data []byte
+	// Metadata contains information about the rendered page.
+	Metadata ScreenshotMetadata
+}
+
+// Save saves a PDF result to disk as name.pdf. Only the last element of name
+// is kept, so the file is always written inside savePath.
+//
+// Parameters:
+//   - name: The base name for the file (without extension)
+//   - savePath: Optional directory path where to save the file (defaults to current directory)
+//
+// Returns the full path to the saved file.
+//
+// Example:
+//
+//	filePath, err := pdf.Save("invoice", "./pdfs")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (p *PDFResult) Save(name string, savePath ...string) (string, error) {
+	if len(p.Data) == 0 {
+		return "", fmt.Errorf("pdf data is empty")
+	}
+	dir := "."
+	if len(savePath) > 0 {
+		dir = savePath[0]
+	}
+	filePath, err := safeJoin(dir, name+".pdf")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return filePath, os.WriteFile(filePath, p.Data, 0644)
+}
@@ -0,0 +1,44 @@
+package scrapfly
+
+import (
+	"net/http"
+	"testing"
+)
+
+var imageSignatures = map[string][]byte{
+	"jpg":  {0xff, 0xd8, 0xff, 0xe0, 0x00, 0x10, 'J', 'F', 'I', 'F', 0x00},
+	"png":  {0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0x00, 0x00, 0x00, 0x0d, 'I', 'H', 'D', 'R'},
+	"webp": append([]byte("RIFF\x24\x00\x00\x00WEBPVP8 "), make([]byte, 16)...),
+	"gif":  []byte("GIF89a\x01\x00\x01\x00\x80\x00\x00"),
+}
+
+func TestNewScreenshotResult_SniffsExtension(t *testing.T) {
+	for _, contentType := range []string{"", "application/octet-stream", "application/octet-stream; charset=binary"} {
+		for want, data := range imageSignatures {
+			resp := &http.Response{Header: http.Header{}}
+			if contentType != "" {
+				resp.Header.Set("Content-Type", contentType)
+			}
+			result, err := newScreenshotResult(resp, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := result.Metadata.ExtensionName; got != want {
+				t.Errorf("content-type %q: extension = %q, want %q", contentType, got, want)
+			}
+		}
+	}
+}
+
+func TestNewScreenshotResult_ExtensionFromHeader(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Content-Type": {"image/webp"}}}
+	result, _ := newScreenshotResult(resp, imageSignatures["png"])
+	if result.Metadata.ExtensionName != "webp" {
+		t.Errorf("extension = %q, want the Content-Type one", result.Metadata.ExtensionName)
+	}
+
+	result, _ = newScreenshotResult(&http.Response{Header: http.Header{}}, []byte("not an image"))
+	if result.Metadata.ExtensionName != "bin" {
+		t.Errorf("extension = %q, want bin", result.Metadata.ExtensionName)
+	}
+}
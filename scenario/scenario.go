@@ -47,6 +47,11 @@
 //	}
 package js_scenario
 
+import (
+	"fmt"
+	"regexp"
+)
+
 // JSScenarioStep represents a single step in the JS scenario.
 type JSScenarioStep = map[string]any
 
@@ -84,6 +89,31 @@ func (b *ScenarioBuilder) Build() ([]JSScenarioStep, error) {
 	return b.steps, nil
 }
 
+// Append adds the steps of another scenario, e.g. a reusable login or
+// cookie-consent fragment, after the steps already in the builder.
+//
+// Example:
+//
+//	acceptCookies := js_scenario.New().Click("#accept-cookies", js_scenario.WithClickIgnoreIfNotVisible(true)).Steps()
+//	steps, err := js_scenario.New().Append(acceptCookies).Click("a.next").Build()
+func (b *ScenarioBuilder) Append(other []JSScenarioStep) *ScenarioBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.steps = append(b.steps, other...)
+	return b
+}
+
+// Concat returns a new scenario made of the steps of all given scenarios, in order.
+// The given scenarios are not modified.
+func Concat(scenarios ...[]JSScenarioStep) []JSScenarioStep {
+	steps := make([]JSScenarioStep, 0)
+	for _, scenario := range scenarios {
+		steps = append(steps, scenario...)
+	}
+	return steps
+}
+
 // --- Click Action ---
 
 // clickParams holds all parameters for a "click" action.
@@ -283,6 +313,74 @@ func (b *ScenarioBuilder) WaitForSelector(selector string, opts ...WaitForSelect
 	return b
 }
 
+// --- Hover Action ---
+
+// hoverParams holds all parameters for a "hover" action.
+type hoverParams struct {
+	Selector string `json:"selector"`
+}
+
+// Hover adds a step to move the mouse over an element matching the selector,
+// e.g. to reveal a dropdown menu or trigger content loaded on hover.
+func (b *ScenarioBuilder) Hover(selector string) *ScenarioBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.steps = append(b.steps, map[string]interface{}{"hover": &hoverParams{Selector: selector}})
+	return b
+}
+
+// --- Drag Action ---
+
+// dragParams holds all parameters for a "drag" action.
+type dragParams struct {
+	FromSelector string `json:"from_selector"`
+	ToSelector   string `json:"to_selector"`
+}
+
+// Drag adds a step to drag the element matching fromSelector and drop it
+// onto the element matching toSelector.
+func (b *ScenarioBuilder) Drag(fromSelector, toSelector string) *ScenarioBuilder {
+	if b.err != nil {
+		return b
+	}
+	params := &dragParams{FromSelector: fromSelector, ToSelector: toSelector}
+	b.steps = append(b.steps, map[string]interface{}{"drag": params})
+	return b
+}
+
+// --- Press Key Action ---
+
+// keyPattern matches the key names accepted by the "press_key" action: a
+// named key (Enter, Tab, ArrowDown, F5...) or a single character, optionally
+// prefixed with modifiers, e.g. "Shift+Tab" or "Control+a".
+// It must be kept in sync with the press_key pattern of JsScenarioSchema.
+const keyPattern = `^((Control|Shift|Alt|Meta)\+)*(Enter|Tab|Escape|Backspace|Delete|Insert|Space|ArrowUp|ArrowDown|ArrowLeft|ArrowRight|Home|End|PageUp|PageDown|F([1-9]|1[0-2])|.)$`
+
+var keyRegex = regexp.MustCompile(keyPattern)
+
+// pressKeyParams holds all parameters for a "press_key" action.
+type pressKeyParams struct {
+	Selector string `json:"selector"`
+	Key      string `json:"key"`
+}
+
+// PressKey adds a step to focus the element matching the selector and press
+// a key on it, e.g. "Enter" to submit a search box or "Tab" to move to the
+// next field. An invalid key name makes Build return an error.
+func (b *ScenarioBuilder) PressKey(selector, key string) *ScenarioBuilder {
+	if b.err != nil {
+		return b
+	}
+	if !keyRegex.MatchString(key) {
+		b.err = fmt.Errorf("%w: invalid key name %q for press_key", ErrInvalidScenario, key)
+		return b
+	}
+	params := &pressKeyParams{Selector: selector, Key: key}
+	b.steps = append(b.steps, map[string]interface{}{"press_key": params})
+	return b
+}
+
 // --- Scroll Action ---
 
 // scrollParams holds all parameters for a "scroll" action.
@@ -0,0 +1,178 @@
+package js_scenario
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFromJSON_RoundTripsBuilder(t *testing.T) {
+	steps, err := New().
+		Fill("input[name=username]", "user123", WithFillClear(true)).
+		Click("button[type='submit']").
+		WaitForNavigation(WithNavTimeout(5000)).
+		WaitForSelector("#dashboard", WithSelectorState(SelectorStateVisible)).
+		Scroll(WithScrollInfinite(2)).
+		Wait(500).
+		Execute("return navigator.userAgent").
+		ConditionOnStatusCode(404, ActionExitFailed).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(steps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := FromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != len(steps) {
+		t.Fatalf("loaded %d steps, want %d", len(loaded), len(steps))
+	}
+	again, err := json.Marshal(loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want, got any
+	_ = json.Unmarshal(data, &want)
+	_ = json.Unmarshal(again, &got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip mismatch:\n got %s\nwant %s", again, data)
+	}
+}
+
+func TestFromJSON_Invalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"not json":         `{`,
+		"not an array":     `{"click": {"selector": "a"}}`,
+		"unknown action":   `[{"teleport": {"selector": "a"}}]`,
+		"missing selector": `[{"click": {}}]`,
+		"unknown option":   `[{"click": {"selector": "a", "force": true}}]`,
+		"negative wait":    `[{"wait": -1}]`,
+	} {
+		if _, err := FromJSON([]byte(data)); !errors.Is(err, ErrInvalidScenario) {
+			t.Errorf("%s: expected ErrInvalidScenario, got %v", name, err)
+		}
+	}
+}
+
+func TestValidate_BuilderSteps(t *testing.T) {
+	if err := Validate(New().Click("a").Wait(100).Steps()); err != nil {
+		t.Errorf("valid steps rejected: %v", err)
+	}
+	if err := Validate([]JSScenarioStep{{"click": map[string]any{"selector": ""}}}); !errors.Is(err, ErrInvalidScenario) {
+		t.Errorf("expected ErrInvalidScenario, got %v", err)
+	}
+}
+
+func TestHoverAndDragSteps(t *testing.T) {
+	steps, err := New().Hover("nav .menu").Drag("#item-1", "#basket").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(steps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"hover":{"selector":"nav .menu"}},{"drag":{"from_selector":"#item-1","to_selector":"#basket"}}]`
+	if string(data) != want {
+		t.Errorf("steps = %s, want %s", data, want)
+	}
+	if err := Validate(steps); err != nil {
+		t.Errorf("hover/drag steps rejected: %v", err)
+	}
+
+	for name, data := range map[string]string{
+		"hover without selector": `[{"hover": {}}]`,
+		"drag without target":    `[{"drag": {"from_selector": "#item-1"}}]`,
+		"drag with empty source": `[{"drag": {"from_selector": "", "to_selector": "#basket"}}]`,
+	} {
+		if _, err := FromJSON([]byte(data)); !errors.Is(err, ErrInvalidScenario) {
+			t.Errorf("%s: expected ErrInvalidScenario, got %v", name, err)
+		}
+	}
+}
+
+func TestFlattenedSchemaMatchesSchema(t *testing.T) {
+	var nested, flattened map[string]any
+	if err := json.Unmarshal([]byte(jsScenarioSchemaString), &nested); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(jsScenarioSchemaFlattenedString), &flattened); err != nil {
+		t.Fatal(err)
+	}
+	refs := nested["$defs"].(map[string]any)["scenarioStep"].(map[string]any)["oneOf"].([]any)
+	steps := flattened["items"].(map[string]any)["oneOf"].([]any)
+	if len(refs) != len(steps) {
+		t.Fatalf("schema has %d step types, flattened schema has %d", len(refs), len(steps))
+	}
+}
+
+func TestPressKeyStep(t *testing.T) {
+	steps, err := New().Fill("input[name=q]", "scrapfly").PressKey("input[name=q]", "Enter").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(steps[1])
+	if want := `{"press_key":{"selector":"input[name=q]","key":"Enter"}}`; string(data) != want {
+		t.Errorf("step = %s, want %s", data, want)
+	}
+	if err := Validate(steps); err != nil {
+		t.Errorf("press_key step rejected: %v", err)
+	}
+
+	for _, key := range []string{"Tab", "Shift+Tab", "Control+a", "F12", "ArrowDown", "x"} {
+		if _, err := New().PressKey("input", key).Build(); err != nil {
+			t.Errorf("key %q rejected: %v", key, err)
+		}
+	}
+	for _, key := range []string{"", "enter", "F13", "Hyper+a", "ab"} {
+		if _, err := New().PressKey("input", key).Build(); !errors.Is(err, ErrInvalidScenario) {
+			t.Errorf("key %q: expected ErrInvalidScenario, got %v", key, err)
+		}
+		step := fmt.Sprintf(`[{"press_key": {"selector": "input", "key": %q}}]`, key)
+		if _, err := FromJSON([]byte(step)); !errors.Is(err, ErrInvalidScenario) {
+			t.Errorf("schema accepted key %q", key)
+		}
+	}
+}
+
+func TestKeyPatternMatchesSchema(t *testing.T) {
+	for _, schema := range []string{jsScenarioSchemaString, jsScenarioSchemaFlattenedString} {
+		pattern, _ := json.Marshal(keyPattern)
+		if !strings.Contains(schema, `"pattern": `+string(pattern)) {
+			t.Error("press_key schema pattern is out of sync with keyPattern")
+		}
+	}
+}
+
+func TestConcatAndAppend(t *testing.T) {
+	login := New().Fill("#user", "me").Click("#login").Steps()
+	cookies := New().Click("#accept-cookies").Steps()
+
+	steps := Concat(cookies, login, nil)
+	if len(steps) != 3 || steps[0]["click"] == nil || steps[1]["fill"] == nil {
+		t.Fatalf("concat = %v", steps)
+	}
+	steps[0] = JSScenarioStep{"wait": 1}
+	if cookies[0]["click"] == nil {
+		t.Error("Concat modified its input")
+	}
+
+	built, err := New().Append(cookies).Append(login).Wait(100).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(built) != 4 || built[0]["click"] == nil || built[3]["wait"] != 100 {
+		t.Errorf("append = %v", built)
+	}
+	if err := Validate(built); err != nil {
+		t.Errorf("appended steps rejected: %v", err)
+	}
+}
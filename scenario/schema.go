@@ -2,6 +2,8 @@ package js_scenario
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 
 	"github.com/google/jsonschema-go/jsonschema"
 )
@@ -54,6 +56,15 @@ const jsScenarioSchemaString = `
         },
         {
           "$ref": "#/$defs/waitForSelectorStep"
+        },
+        {
+          "$ref": "#/$defs/hoverStep"
+        },
+        {
+          "$ref": "#/$defs/dragStep"
+        },
+        {
+          "$ref": "#/$defs/pressKeyStep"
         }
       ]
     },
@@ -309,6 +320,85 @@ const jsScenarioSchemaString = `
         "wait_for_selector"
       ],
       "additionalProperties": false
+    },
+    "hoverStep": {
+      "title": "Hover Step",
+      "type": "object",
+      "properties": {
+        "hover": {
+          "type": "object",
+          "properties": {
+            "selector": {
+              "type": "string",
+              "minLength": 1
+            }
+          },
+          "required": [
+            "selector"
+          ],
+          "additionalProperties": false
+        }
+      },
+      "required": [
+        "hover"
+      ],
+      "additionalProperties": false
+    },
+    "dragStep": {
+      "title": "Drag Step",
+      "type": "object",
+      "properties": {
+        "drag": {
+          "type": "object",
+          "properties": {
+            "from_selector": {
+              "type": "string",
+              "minLength": 1
+            },
+            "to_selector": {
+              "type": "string",
+              "minLength": 1
+            }
+          },
+          "required": [
+            "from_selector",
+            "to_selector"
+          ],
+          "additionalProperties": false
+        }
+      },
+      "required": [
+        "drag"
+      ],
+      "additionalProperties": false
+    },
+    "pressKeyStep": {
+      "title": "PressKey Step",
+      "type": "object",
+      "properties": {
+        "press_key": {
+          "type": "object",
+          "properties": {
+            "selector": {
+              "type": "string",
+              "minLength": 1
+            },
+            "key": {
+              "type": "string",
+              "pattern": "^((Control|Shift|Alt|Meta)\\+)*(Enter|Tab|Escape|Backspace|Delete|Insert|Space|ArrowUp|ArrowDown|ArrowLeft|ArrowRight|Home|End|PageUp|PageDown|F([1-9]|1[0-2])|.)$"
+            }
+          },
+          "required": [
+            "selector",
+            "key"
+          ],
+          "additionalProperties": false
+        }
+      },
+      "required": [
+        "press_key"
+      ],
+      "additionalProperties": false
     }
   }
 }
@@ -590,6 +680,85 @@ const jsScenarioSchemaFlattenedString = `
           "wait_for_selector"
         ],
         "additionalProperties": false
+      },
+      {
+        "title": "Hover Step",
+        "type": "object",
+        "properties": {
+          "hover": {
+            "type": "object",
+            "properties": {
+              "selector": {
+                "type": "string",
+                "minLength": 1
+              }
+            },
+            "required": [
+              "selector"
+            ],
+            "additionalProperties": false
+          }
+        },
+        "required": [
+          "hover"
+        ],
+        "additionalProperties": false
+      },
+      {
+        "title": "Drag Step",
+        "type": "object",
+        "properties": {
+          "drag": {
+            "type": "object",
+            "properties": {
+              "from_selector": {
+                "type": "string",
+                "minLength": 1
+              },
+              "to_selector": {
+                "type": "string",
+                "minLength": 1
+              }
+            },
+            "required": [
+              "from_selector",
+              "to_selector"
+            ],
+            "additionalProperties": false
+          }
+        },
+        "required": [
+          "drag"
+        ],
+        "additionalProperties": false
+      },
+      {
+        "title": "PressKey Step",
+        "type": "object",
+        "properties": {
+          "press_key": {
+            "type": "object",
+            "properties": {
+              "selector": {
+                "type": "string",
+                "minLength": 1
+              },
+              "key": {
+                "type": "string",
+                "pattern": "^((Control|Shift|Alt|Meta)\\+)*(Enter|Tab|Escape|Backspace|Delete|Insert|Space|ArrowUp|ArrowDown|ArrowLeft|ArrowRight|Home|End|PageUp|PageDown|F([1-9]|1[0-2])|.)$"
+              }
+            },
+            "required": [
+              "selector",
+              "key"
+            ],
+            "additionalProperties": false
+          }
+        },
+        "required": [
+          "press_key"
+        ],
+        "additionalProperties": false
       }
     ]
   }
@@ -604,6 +773,12 @@ var JsScenarioSchemaFlattened *jsonschema.Schema
 // Use it with more capable models or where compatibility with recent meta-schemas is required.
 var JsScenarioSchema *jsonschema.Schema
 
+// jsScenarioSchemaResolved is JsScenarioSchema resolved for validation.
+var jsScenarioSchemaResolved *jsonschema.Resolved
+
+// ErrInvalidScenario is returned when a scenario does not match JsScenarioSchema.
+var ErrInvalidScenario = errors.New("invalid js scenario")
+
 func init() {
 	err := json.Unmarshal([]byte(jsScenarioSchemaString), &JsScenarioSchema)
 	if err != nil {
@@ -613,4 +788,59 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+
+	// The validator only accepts the 2020-12 dialect, which the schema is
+	// compatible with, so resolve a copy without the draft-07 $schema.
+	var schema *jsonschema.Schema
+	if err := json.Unmarshal([]byte(jsScenarioSchemaString), &schema); err != nil {
+		panic(err)
+	}
+	schema.Schema = ""
+	jsScenarioSchemaResolved, err = schema.Resolve(nil)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Validate checks the steps against JsScenarioSchema.
+func Validate(steps []JSScenarioStep) error {
+	data, err := json.Marshal(steps)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidScenario, err)
+	}
+	return validateJSON(data)
+}
+
+// FromJSON parses a JSON scenario, as produced by marshalling the builder
+// steps, and validates it against JsScenarioSchema.
+// The returned steps can be used as ScrapeConfig.JSScenario.
+//
+// Example:
+//
+//	data, _ := os.ReadFile("login_scenario.json")
+//	steps, err := js_scenario.FromJSON(data)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	config := &scrapfly.ScrapeConfig{URL: url, RenderJS: true, JSScenario: steps}
+func FromJSON(data []byte) ([]JSScenarioStep, error) {
+	if err := validateJSON(data); err != nil {
+		return nil, err
+	}
+	var steps []JSScenarioStep
+	if err := json.Unmarshal(data, &steps); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidScenario, err)
+	}
+	return steps, nil
+}
+
+func validateJSON(data []byte) error {
+	var instance any
+	if err := json.Unmarshal(data, &instance); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidScenario, err)
+	}
+	if err := jsScenarioSchemaResolved.Validate(instance); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidScenario, err)
+	}
+	return nil
 }
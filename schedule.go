@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -18,6 +20,99 @@ type ScheduleRecurrence struct {
 	Ends     *ScheduleEnd `json:"ends,omitempty"`
 }
 
+// validate checks the cron expression of the recurrence, if any, so a
+// malformed one fails before reaching the API.
+func (r *ScheduleRecurrence) validate() error {
+	if r == nil || r.Cron == "" {
+		return nil
+	}
+	if err := validateCron(r.Cron); err != nil {
+		return fmt.Errorf("%w: invalid cron expression %q: %w", ErrScheduleConfig, r.Cron, err)
+	}
+	return nil
+}
+
+// cronFields are the bounds of the five fields of a cron expression.
+var cronFields = []struct {
+	name     string
+	min, max int
+	names    []string
+}{
+	{"minute", 0, 59, nil},
+	{"hour", 0, 23, nil},
+	{"day of month", 1, 31, nil},
+	{"month", 1, 12, []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{"day of week", 0, 7, []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+var cronMacros = []string{"@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"}
+
+// validateCron checks a standard five field cron expression ("*/15 9-17 *
+// * mon-fri"), or one of the @daily style macros.
+func validateCron(expr string) error {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "@") {
+		if !slices.Contains(cronMacros, strings.ToLower(expr)) {
+			return fmt.Errorf("unknown macro, expected one of %v", cronMacros)
+		}
+		return nil
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("expected %d fields (minute hour day-of-month month day-of-week), got %d", len(cronFields), len(fields))
+	}
+	for i, field := range fields {
+		spec := cronFields[i]
+		for _, part := range strings.Split(field, ",") {
+			if err := validateCronPart(part, spec.min, spec.max, spec.names); err != nil {
+				return fmt.Errorf("%s field %q: %w", spec.name, field, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateCronPart checks one comma separated element of a cron field: "*",
+// a value or a range, optionally followed by a "/step".
+func validateCronPart(part string, min, max int, names []string) error {
+	base, step, hasStep := strings.Cut(part, "/")
+	if hasStep {
+		if n, err := strconv.Atoi(step); err != nil || n <= 0 {
+			return fmt.Errorf("invalid step %q", step)
+		}
+	}
+	if base == "*" {
+		return nil
+	}
+	low, high, isRange := strings.Cut(base, "-")
+	lowValue, err := cronValue(low, min, max, names)
+	if err != nil {
+		return err
+	}
+	if !isRange {
+		return nil
+	}
+	highValue, err := cronValue(high, min, max, names)
+	if err != nil {
+		return err
+	}
+	if lowValue > highValue {
+		return fmt.Errorf("range %q is reversed", base)
+	}
+	return nil
+}
+
+func cronValue(value string, min, max int, names []string) (int, error) {
+	if i := slices.Index(names, strings.ToLower(value)); i >= 0 {
+		return i + min, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("value %q out of range %d-%d", value, min, max)
+	}
+	return n, nil
+}
+
 type ScheduleEnd struct {
 	Type  string  `json:"type"` // "date" | "count"
 	Date  *string `json:"date,omitempty"`
@@ -88,6 +183,9 @@ func (c *Client) createSchedule(path, configKey string, config map[string]interf
 	if req == nil {
 		req = &CreateScheduleRequest{}
 	}
+	if err := req.Recurrence.validate(); err != nil {
+		return nil, err
+	}
 	body := map[string]interface{}{
 		configKey:           config,
 		"webhook_name":      req.WebhookName,
@@ -158,6 +256,9 @@ func (c *Client) UpdateSchedule(id string, req *UpdateScheduleRequest) (*Schedul
 	if req == nil {
 		return nil, fmt.Errorf("update request is required")
 	}
+	if err := req.Recurrence.validate(); err != nil {
+		return nil, err
+	}
 	var out Schedule
 	if err := c.scheduleRequest("PATCH", "/schedules/"+url.PathEscape(id), "", req, &out); err != nil {
 		return nil, err
@@ -194,12 +295,12 @@ func (c *Client) ExecuteSchedule(id string) (*Schedule, error) {
 }
 
 func (c *Client) scheduleRequest(method, path, extraQuery string, body interface{}, out interface{}) error {
-	endpointURL, err := url.Parse(c.host + path)
+	endpointURL, err := url.Parse(c.endpoint(path))
 	if err != nil {
 		return err
 	}
 	q := endpointURL.Query()
-	q.Set("key", c.key)
+	q.Set("key", c.APIKey())
 	if extraQuery != "" {
 		extra, _ := url.ParseQuery(extraQuery)
 		for k, vs := range extra {
@@ -223,13 +324,13 @@ func (c *Client) scheduleRequest(method, path, extraQuery string, body interface
 	if err != nil {
 		return err
 	}
-	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 	req.Header.Set("Accept", "application/json")
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
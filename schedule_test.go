@@ -0,0 +1,31 @@
+package scrapfly
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestValidateCron(t *testing.T) {
+	for _, expr := range []string{"0 * * * *", "*/15 9-17 * * mon-fri", "0 0 1,15 jan-jun 0", "30 2 * * 7", "@daily", "@HOURLY"} {
+		if err := validateCron(expr); err != nil {
+			t.Errorf("%q rejected: %v", expr, err)
+		}
+	}
+	for _, expr := range []string{"", "* * * *", "0 0 * * * *", "60 * * * *", "0 24 * * *", "0 0 0 * *", "*/0 * * * *", "5-1 * * * *", "0 0 * foo *", "@every 5m"} {
+		if err := validateCron(expr); err == nil {
+			t.Errorf("%q accepted", expr)
+		}
+	}
+}
+
+func TestClient_CreateScrapeScheduleRejectsInvalidCron(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	})
+	req := &CreateScheduleRequest{Recurrence: &ScheduleRecurrence{Cron: "0 25 * * *"}}
+	_, err := client.CreateScrapeSchedule(map[string]interface{}{"url": "https://example.com"}, req)
+	if !errors.Is(err, ErrScheduleConfig) {
+		t.Errorf("expected ErrScheduleConfig, got %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package scrapfly
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxSitemapDepth caps how many levels of nested sitemap indexes
+// ScrapeSitemap follows.
+const maxSitemapDepth = 3
+
+// maxSitemapBytes caps the decompressed size of a gzip compressed sitemap,
+// following the 50 MiB limit of the sitemaps.org protocol. The client's
+// MaxResponseBytes takes over when set.
+const maxSitemapBytes = 50 << 20
+
+// sitemapDocument is either a <urlset> or a <sitemapindex>; both list their
+// entries as <loc> elements.
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []sitemapLoc `xml:"url"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+type sitemapLoc struct {
+	Loc string `xml:"loc"`
+}
+
+// ScrapeSitemap scrapes a sitemap and returns the page URLs it lists.
+//
+// Sitemap indexes (<sitemapindex>) are followed recursively, up to 3 levels
+// deep, and gzip compressed sitemaps (sitemap.xml.gz) are decompressed up to
+// 50 MiB, or the client's MaxResponseBytes when set, failing with
+// ErrResponseTooLarge beyond.
+// Each sitemap is fetched with Scrape using a copy of config (which may be
+// nil) with its URL replaced. URLs are returned once, in document order.
+//
+// Example:
+//
+//	urls, err := client.ScrapeSitemap("https://web-scraping.dev/sitemap.xml", &scrapfly.ScrapeConfig{Country: "us"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(len(urls), "pages")
+func (c *Client) ScrapeSitemap(sitemapURL string, config *ScrapeConfig) ([]string, error) {
+	var template ScrapeConfig
+	if config != nil {
+		template = *config
+	}
+	walker := &sitemapWalker{client: c, template: template, visited: map[string]bool{}, seen: map[string]bool{}}
+	if err := walker.walk(sitemapURL, 0); err != nil {
+		return nil, err
+	}
+	return walker.urls, nil
+}
+
+// sitemapWalker accumulates the URLs of a sitemap tree.
+type sitemapWalker struct {
+	client   *Client
+	template ScrapeConfig
+	visited  map[string]bool
+	seen     map[string]bool
+	urls     []string
+}
+
+func (w *sitemapWalker) walk(sitemapURL string, depth int) error {
+	if w.visited[sitemapURL] {
+		return nil
+	}
+	w.visited[sitemapURL] = true
+
+	config := w.template
+	config.URL = sitemapURL
+	result, err := w.client.Scrape(&config)
+	if err != nil {
+		return fmt.Errorf("failed to scrape sitemap %s: %w", sitemapURL, err)
+	}
+	data, err := result.RawBytes()
+	if err != nil {
+		return fmt.Errorf("failed to read sitemap %s: %w", sitemapURL, err)
+	}
+	limit := int64(maxSitemapBytes)
+	if w.client.maxResponseBytes > 0 {
+		limit = w.client.maxResponseBytes
+	}
+	doc, err := parseSitemap(data, limit)
+	if err != nil {
+		return fmt.Errorf("failed to parse sitemap %s: %w", sitemapURL, err)
+	}
+
+	for _, entry := range doc.URLs {
+		loc := strings.TrimSpace(entry.Loc)
+		if loc != "" && !w.seen[loc] {
+			w.seen[loc] = true
+			w.urls = append(w.urls, loc)
+		}
+	}
+	if len(doc.Sitemaps) == 0 {
+		return nil
+	}
+	if depth >= maxSitemapDepth {
+		DefaultLogger.Warn("sitemap index", sitemapURL, "exceeds max depth", maxSitemapDepth, "skipping nested sitemaps")
+		return nil
+	}
+	for _, entry := range doc.Sitemaps {
+		if loc := strings.TrimSpace(entry.Loc); loc != "" {
+			if err := w.walk(loc, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parseSitemap decodes a <urlset> or <sitemapindex> document, decompressing
+// it first when gzip compressed. Decompressed content over limit bytes fails
+// with ErrResponseTooLarge.
+func parseSitemap(data []byte, limit int64) (*sitemapDocument, error) {
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		if data, err = io.ReadAll(io.LimitReader(reader, limit+1)); err != nil {
+			return nil, err
+		}
+		if int64(len(data)) > limit {
+			return nil, fmt.Errorf("%w: decompressed sitemap exceeds %d bytes", ErrResponseTooLarge, limit)
+		}
+	}
+	var doc sitemapDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.XMLName.Local != "urlset" && doc.XMLName.Local != "sitemapindex" {
+		return nil, fmt.Errorf("%w: expected <urlset> or <sitemapindex>, got <%s>", ErrContentType, doc.XMLName.Local)
+	}
+	return &doc, nil
+}
@@ -0,0 +1,141 @@
+package scrapfly
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sitemapIndex = `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>https://web-scraping.dev/sitemap-products.xml</loc></sitemap>
+  <sitemap><loc>https://web-scraping.dev/sitemap-blog.xml.gz</loc></sitemap>
+</sitemapindex>`
+
+const productsSitemap = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://web-scraping.dev/product/1</loc><lastmod>2024-01-01</lastmod></url>
+  <url><loc> https://web-scraping.dev/product/2 </loc></url>
+</urlset>`
+
+const blogSitemap = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://web-scraping.dev/blog/1</loc></url>
+  <url><loc>https://web-scraping.dev/product/1</loc></url>
+</urlset>`
+
+func gzipBase64(t *testing.T, content string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write([]byte(content))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// sitemapResponse wraps content in a successful scrape response.
+func sitemapResponse(content, format string) []byte {
+	body, _ := json.Marshal(map[string]interface{}{
+		"result": map[string]interface{}{
+			"success": true, "status": "DONE", "status_code": 200,
+			"content": content, "format": format, "content_type": "application/xml",
+		},
+	})
+	return body
+}
+
+func TestClient_ScrapeSitemapIndex(t *testing.T) {
+	var countries []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		countries = append(countries, r.URL.Query().Get("country"))
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("url") {
+		case "https://web-scraping.dev/sitemap.xml":
+			_, _ = w.Write(sitemapResponse(sitemapIndex, "text"))
+		case "https://web-scraping.dev/sitemap-products.xml":
+			_, _ = w.Write(sitemapResponse(productsSitemap, "text"))
+		case "https://web-scraping.dev/sitemap-blog.xml.gz":
+			_, _ = w.Write(sitemapResponse(gzipBase64(t, blogSitemap), "binary"))
+		default:
+			t.Errorf("unexpected url %s", r.URL.Query().Get("url"))
+		}
+	})
+
+	urls, err := client.ScrapeSitemap("https://web-scraping.dev/sitemap.xml", &ScrapeConfig{Country: "us"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"https://web-scraping.dev/product/1",
+		"https://web-scraping.dev/product/2",
+		"https://web-scraping.dev/blog/1",
+	}
+	if !reflect.DeepEqual(urls, want) {
+		t.Errorf("urls = %v, want %v", urls, want)
+	}
+	if len(countries) != 3 || countries[2] != "us" {
+		t.Errorf("config template not applied: countries = %v", countries)
+	}
+}
+
+func TestClient_ScrapeSitemapDepthLimit(t *testing.T) {
+	var calls int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		// every sitemap points to a deeper one
+		next := r.URL.Query().Get("url") + "x"
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(sitemapResponse(`<sitemapindex><sitemap><loc>`+next+`</loc></sitemap></sitemapindex>`, "text"))
+	})
+
+	urls, err := client.ScrapeSitemap("https://example.com/s", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(urls) != 0 || calls != maxSitemapDepth+1 {
+		t.Errorf("urls = %v, calls = %d, want %d", urls, calls, maxSitemapDepth+1)
+	}
+}
+
+func TestClient_ScrapeSitemapNotASitemap(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(sitemapResponse(`<html><body>not found</body></html>`, "text"))
+	})
+	if _, err := client.ScrapeSitemap("https://example.com/sitemap.xml", nil); !errors.Is(err, ErrContentType) {
+		t.Errorf("expected ErrContentType, got %v", err)
+	}
+}
+
+func TestClient_ScrapeSitemapLimitsDecompressedSize(t *testing.T) {
+	large := `<?xml version="1.0" encoding="UTF-8"?><urlset>` + strings.Repeat(" ", 4096) + `</urlset>`
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(sitemapResponse(gzipBase64(t, large), "binary"))
+	})
+	client.SetMaxResponseBytes(1024)
+
+	if _, err := client.ScrapeSitemap("https://web-scraping.dev/sitemap.xml.gz", nil); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+	if _, err := parseSitemap(gzipBytes(t, large), int64(len(large))); err != nil {
+		t.Errorf("sitemap at the limit: %v", err)
+	}
+}
+
+func gzipBytes(t *testing.T, content string) []byte {
+	t.Helper()
+	data, err := base64.StdEncoding.DecodeString(gzipBase64(t, content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -0,0 +1,92 @@
+package scrapfly
+
+import (
+	"context"
+)
+
+// Tracer starts spans around Client API calls (Scrape, Screenshot, Extract).
+//
+// It is deliberately small so the SDK does not depend on OpenTelemetry: wrap
+// an OpenTelemetry tracer in a few lines and install it with Client.SetTracer.
+type Tracer interface {
+	// Start starts a span named name, as a child of the span carried by ctx
+	// if any, and returns a context carrying the new span. That context is
+	// used for the outbound HTTP requests of the call. Start may return a
+	// nil Span to skip tracing the call.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// SetAttribute tags the span, e.g. "scrapfly.url" or "scrapfly.cost".
+	SetAttribute(key string, value any)
+	// RecordError marks the span as failed with err.
+	RecordError(err error)
+	// End completes the span.
+	End()
+}
+
+// SetTracer installs a Tracer creating spans named "scrapfly.Scrape",
+// "scrapfly.Screenshot" and "scrapfly.Extract" around API calls. Passing nil
+// disables tracing (default). Spans are tagged with:
+//
+//	scrapfly.url                   target URL
+//	scrapfly.render_js             scrapes only
+//	scrapfly.status                scrape status, e.g. DONE
+//	scrapfly.status_code           HTTP status of the API response (screenshots, extractions)
+//	scrapfly.upstream_status_code  HTTP status of the target website
+//	scrapfly.cost                  API credits billed, when reported, summed over
+//	                               the attempts of an escalated scrape
+//	scrapfly.escalated             true when a blocked scrape was retried with
+//	                               ScrapeConfig.EscalateProxyPool
+//
+// Outbound requests carry the span context, so trace headers can be
+// propagated with an instrumented transport (SetHTTPClient) or a
+// RequestInterceptor reading req.Context().
+//
+// Example — OpenTelemetry adapter, only tracing calls made within a trace:
+//
+//	type otelTracer struct{ tracer trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string) (context.Context, scrapfly.Span) {
+//	    if !trace.SpanContextFromContext(ctx).IsValid() {
+//	        return ctx, nil
+//	    }
+//	    ctx, span := t.tracer.Start(ctx, name)
+//	    return ctx, otelSpan{span}
+//	}
+//
+//	client.SetTracer(otelTracer{otel.Tracer("scrapfly")})
+//	client.AddRequestInterceptor(func(req *http.Request) {
+//	    otel.GetTextMapPropagator().Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+//	})
+func (c *Client) SetTracer(tracer Tracer) {
+	c.tracer = tracer
+}
+
+// noopSpan is used when no Tracer is installed.
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, any) {}
+func (noopSpan) RecordError(error)        {}
+func (noopSpan) End()                     {}
+
+// startSpan starts a span with the installed Tracer, if any.
+func (c *Client) startSpan(ctx context.Context, name string) (context.Context, Span) {
+	if c.tracer == nil {
+		return ctx, noopSpan{}
+	}
+	spanCtx, span := c.tracer.Start(ctx, name)
+	if span == nil {
+		return ctx, noopSpan{}
+	}
+	return spanCtx, span
+}
+
+// endSpan records err, if any, and ends span.
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
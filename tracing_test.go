@@ -0,0 +1,136 @@
+package scrapfly
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type spanContextKey struct{}
+
+type recordedSpan struct {
+	name       string
+	attributes map[string]any
+	err        error
+	ended      bool
+}
+
+func (s *recordedSpan) SetAttribute(key string, value any) { s.attributes[key] = value }
+func (s *recordedSpan) RecordError(err error)              { s.err = err }
+func (s *recordedSpan) End()                               { s.ended = true }
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span := &recordedSpan{name: name, attributes: map[string]any{}}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+func TestClient_TracerWrapsScrape(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"result": {"success": true, "status": "DONE", "status_code": 201, "content": "ok"}, "context": {"cost": {"total": 6}}}`))
+	})
+	tracer := &recordingTracer{}
+	client.SetTracer(tracer)
+
+	var requestSpan any
+	client.AddRequestInterceptor(func(req *http.Request) {
+		requestSpan = req.Context().Value(spanContextKey{})
+	})
+
+	if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com", RenderJS: true}); err != nil {
+		t.Fatal(err)
+	}
+	if len(tracer.spans) != 1 {
+		t.Fatalf("spans = %d, want 1", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	if span.name != "scrapfly.Scrape" || !span.ended || span.err != nil {
+		t.Errorf("span = %+v", span)
+	}
+	if span.attributes["scrapfly.url"] != "https://example.com" || span.attributes["scrapfly.render_js"] != true {
+		t.Errorf("attributes = %v", span.attributes)
+	}
+	if span.attributes["scrapfly.cost"] != 6 || span.attributes["scrapfly.upstream_status_code"] != 201 || span.attributes["scrapfly.status"] != "DONE" {
+		t.Errorf("attributes = %v", span.attributes)
+	}
+	if requestSpan != span {
+		t.Error("outbound request does not carry the span context")
+	}
+}
+
+func TestClient_TracerAndMetricsCountEveryEscalationAttempt(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&calls, 1) == 1 {
+			_, _ = w.Write([]byte(`{"result": {"success": true, "status": "DONE", "status_code": 403, "content": "denied"}, "context": {"cost": {"total": 1}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"result": {"success": true, "status": "DONE", "status_code": 200, "content": "ok"}, "context": {"cost": {"total": 25}}}`))
+	})
+	tracer := &recordingTracer{}
+	client.SetTracer(tracer)
+	metrics := &recordingMetrics{}
+	client.SetMetrics(metrics)
+
+	if _, err := client.Scrape(&ScrapeConfig{URL: "https://example.com", EscalateProxyPool: true}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 25}; !slices.Equal(metrics.costs, want) {
+		t.Errorf("observed costs = %v, want %v", metrics.costs, want)
+	}
+	span := tracer.spans[0]
+	if span.attributes["scrapfly.cost"] != 26 || span.attributes["scrapfly.escalated"] != true {
+		t.Errorf("attributes = %v", span.attributes)
+	}
+}
+
+func TestClient_TracerRecordsErrors(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Scrapfly-Api-Cost", "0")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"code": "ERR::EXTRACTION::INVALID_RULE", "message": "invalid", "http_code": 422}`))
+	})
+	tracer := &recordingTracer{}
+	client.SetTracer(tracer)
+
+	if _, err := client.Extract(&ExtractionConfig{Body: []byte("<html></html>"), ContentType: "text/html", ExtractionModel: ExtractionModelProduct}); err == nil {
+		t.Fatal("expected error")
+	}
+	span := tracer.spans[0]
+	if span.name != "scrapfly.Extract" || !span.ended || span.err == nil {
+		t.Errorf("span = %+v", span)
+	}
+	if span.attributes["scrapfly.status_code"] != http.StatusUnprocessableEntity || span.attributes["scrapfly.cost"] != 0 {
+		t.Errorf("attributes = %v", span.attributes)
+	}
+}
+
+type skippingTracer struct{}
+
+func (skippingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, nil
+}
+
+func TestClient_TracerMaySkipSpans(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("png"))
+	})
+	client.SetTracer(skippingTracer{})
+	if _, err := client.Screenshot(&ScreenshotConfig{URL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+}
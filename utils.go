@@ -1,12 +1,25 @@
 package scrapfly
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"path"
+	"path/filepath"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
 	"time"
 )
 
@@ -16,15 +29,42 @@ func urlSafeB64Encode(data string) string {
 	return base64.RawURLEncoding.EncodeToString([]byte(data))
 }
 
+// newUUID returns a random (version 4) UUID.
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// remarshal converts a loosely typed value decoded from an API response
+// (maps, slices, interface{}) into the typed value pointed to by out by
+// round-tripping it through JSON.
+func remarshal(in interface{}, out interface{}) error {
+	buf, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, out)
+}
+
 // fetchWithRetry performs an HTTP request with automatic retry logic for 5xx errors.
 //
 // It retries the request up to the specified number of times with a delay between attempts.
-// Only server errors (5xx status codes) and network errors are retried.
+// Only server errors (5xx status codes) and transient network errors (see
+// isRetryableError) are retried; other errors are returned immediately.
 // The request body must support re-reading via req.GetBody for retries to work properly.
-func fetchWithRetry(client *http.Client, req *http.Request, retries int, delay time.Duration) (*http.Response, error) {
+func fetchWithRetry(c *Client, req *http.Request, retries int, delay time.Duration) (*http.Response, error) {
 	var lastErr error
+	start := time.Now()
 
 	for attempt := 0; attempt < retries; attempt++ {
+		if attempt > 0 {
+			c.metricsSink().ObserveRetry(metricsEndpoint(req.URL, c.pathPrefix))
+		}
 		// We need to be able to re-read the body on retries
 		var bodyReader io.ReadCloser
 		if req.Body != nil {
@@ -38,27 +78,127 @@ func fetchWithRetry(client *http.Client, req *http.Request, retries int, delay t
 			req.Body = bodyReader
 		}
 
-		resp, err := client.Do(req)
+		resp, err := c.do(req)
 		if err != nil {
+			if ctxErr := req.Context().Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			if !isRetryableError(err) {
+				return nil, err
+			}
 			lastErr = err
-			DefaultLogger.Debug("request failed:", err, "retrying...")
-			time.Sleep(delay)
-			continue
-		}
-
-		if resp.StatusCode >= 500 && resp.StatusCode < 600 {
+		} else if resp.StatusCode >= 500 && resp.StatusCode < 600 {
 			resp.Body.Close() // Close body to prevent resource leaks
 			lastErr = &APIError{Message: "server error", HTTPStatusCode: resp.StatusCode}
-			DefaultLogger.Debug("request failed with status", resp.StatusCode, "retrying...")
-			time.Sleep(delay)
-			continue
+		} else {
+			return resp, nil
 		}
 
-		return resp, nil
+		fields := []interface{}{"method", req.Method, "path", req.URL.Path, "attempt", attempt + 1, "max_attempts", retries}
+		if err != nil {
+			fields = append(fields, "error", err)
+		} else {
+			fields = append(fields, "status", resp.StatusCode)
+		}
+		if attempt+1 >= retries {
+			DefaultLogger.debugFields("request failed, giving up", append(fields, "elapsed", time.Since(start))...)
+			break
+		}
+		if !retryBudgetFromContext(req.Context()).take() {
+			DefaultLogger.debugFields("request failed, retry budget exhausted, giving up", append(fields, "elapsed", time.Since(start))...)
+			break
+		}
+		DefaultLogger.debugFields("request failed, retrying", append(fields, "backoff", delay, "elapsed", time.Since(start))...)
+		if err := sleepContext(req.Context(), delay); err != nil {
+			return nil, err
+		}
 	}
 	return nil, lastErr
 }
 
+// isRetryableError reports whether a transport error is worth retrying:
+// timeouts, including the per-attempt http.Client timeout, temporary DNS
+// failures and connections dropped mid-request (ECONNRESET, ECONNABORTED,
+// EPIPE, or io.EOF on a stale keep-alive connection). Every other error is
+// not, notably refused connections, unknown hosts, cancellations, TLS
+// certificate verification failures and request errors such as an
+// unsupported URL scheme.
+//
+// Cancellation and deadlines of the request context are handled by the
+// caller before this is consulted.
+func isRetryableError(err error) bool {
+	var (
+		verifyErr    *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+	)
+	if errors.As(err, &verifyErr) || errors.As(err, &authorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &invalidErr) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE)
+}
+
+// retryBudget caps the retries shared by every request made with a context
+// carrying it, see ConcurrentScrapeOptions.RetryBudget.
+type retryBudget struct {
+	remaining atomic.Int64
+}
+
+type retryBudgetContextKey struct{}
+
+func newRetryBudget(retries int) *retryBudget {
+	budget := &retryBudget{}
+	budget.remaining.Store(int64(retries))
+	return budget
+}
+
+func withRetryBudget(ctx context.Context, budget *retryBudget) context.Context {
+	return context.WithValue(ctx, retryBudgetContextKey{}, budget)
+}
+
+func retryBudgetFromContext(ctx context.Context) *retryBudget {
+	budget, _ := ctx.Value(retryBudgetContextKey{}).(*retryBudget)
+	return budget
+}
+
+// take consumes one retry, reporting false once the budget is spent. A nil
+// budget is unlimited.
+func (b *retryBudget) take() bool {
+	return b == nil || b.remaining.Add(-1) >= 0
+}
+
+// apiCost returns the API credits billed for a call, as reported by the
+// X-Scrapfly-Api-Cost response header.
+func apiCost(resp *http.Response) (int, bool) {
+	cost, err := strconv.Atoi(resp.Header.Get("X-Scrapfly-Api-Cost"))
+	return cost, err == nil
+}
+
+// sleepContext waits for d, returning early with ctx.Err() if ctx is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // ValidateExclusiveFields checks a struct for fields marked with the "exclusive" tag
 // and ensures that only one field per exclusive group is set.
 func ValidateExclusiveFields(s interface{}) error {
@@ -187,3 +327,64 @@ func validateSingleEnumValue(v reflect.Value, fieldName string) error {
 
 	return nil
 }
+
+// cloneJSONMap deep copies a JSON-like map, recursing into nested maps and
+// slices. Other values are copied as is.
+func cloneJSONMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	clone := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		clone[key] = cloneJSONValue(value)
+	}
+	return clone
+}
+
+func cloneJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return cloneJSONMap(v)
+	case []interface{}:
+		clone := make([]interface{}, len(v))
+		for i, item := range v {
+			clone[i] = cloneJSONValue(item)
+		}
+		return clone
+	case []map[string]interface{}:
+		clone := make([]map[string]interface{}, len(v))
+		for i, item := range v {
+			clone[i] = cloneJSONMap(item)
+		}
+		return clone
+	case []string:
+		return slices.Clone(v)
+	default:
+		return value
+	}
+}
+
+// safeJoin joins dir and a filename received from the API or a target
+// website, keeping only the last element of name so the file can't be
+// written outside of dir ("../../etc/cron.d/x" becomes dir/x).
+func safeJoin(dir, name string) (string, error) {
+	base, err := safeFilename(name)
+	if err != nil {
+		return "", err
+	}
+	filePath := filepath.Join(dir, base)
+	if rel, err := filepath.Rel(dir, filePath); err != nil || rel != base {
+		return "", fmt.Errorf("unsafe filename %q", name)
+	}
+	return filePath, nil
+}
+
+// safeFilename returns the last element of name, the filename safeJoin
+// writes to.
+func safeFilename(name string) (string, error) {
+	base := path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if base == "" || base == "." || base == ".." || base == "/" || strings.ContainsRune(base, 0) {
+		return "", fmt.Errorf("unsafe filename %q", name)
+	}
+	return base, nil
+}